@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+
+	wl "github.com/segovia-no/wadtogo/wadloader"
+)
+
+func processWAD2File(filePath string) {
+	wad := wl.WAD2Loader{}
+
+	err := wad.OpenAndLoad(filePath)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	defer wad.Close()
+
+	processWAD2(&wad, flagReader)
+}
+
+func processWAD2(wad *wl.WAD2Loader, flags Flags) {
+	fmt.Println("WAD Filename:", wad.WADFilename)
+	fmt.Println("WAD Type:", string(wad.WADHeader.WadType[:]))
+	fmt.Println("# Entries:", len(wad.Entries))
+	fmt.Println("--------------------------")
+
+	if flags.listEntries {
+		wl.PrintWAD2Entries(wad.Entries)
+	}
+
+	if flags.dumpLumpsInfo != "" {
+		wl.DumpWAD2EntriesToTextFile(flags.dumpLumpsInfo, wad.Entries)
+	}
+
+	if flags.exportSprites != "" {
+		fmt.Println("Exporting textures...")
+
+		if flags.importPalette != "" {
+			palette, err := wl.ImportPalette(flags.importPalette)
+			if err != nil {
+				fmt.Println(err.Error())
+				return
+			}
+
+			wad.Palette = palette
+			wad.HasPalette = true
+		} else if !wad.IsWAD3() {
+			err := wad.DetectPalette()
+			if err != nil {
+				fmt.Println(err.Error())
+			}
+		}
+
+		wad.LoadTextures()
+
+		err := wad.ExportAllTextures(flags.exportSprites, flags.exportMipLevels)
+		if err != nil {
+			fmt.Println("[Error] Cannot export textures - " + err.Error())
+			return
+		}
+
+		fmt.Println("Textures exported successfully")
+	}
+}
+
+func processPAKFile(filePath string) {
+	pak := wl.PAKLoader{}
+
+	err := pak.OpenAndLoad(filePath)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	defer pak.Close()
+
+	fmt.Println("PAK Filename:", pak.PAKFilename)
+	fmt.Println("# Entries:", len(pak.Entries))
+	fmt.Println("--------------------------")
+
+	if flagReader.listEntries {
+		wl.PrintPAKEntries(pak.Entries)
+	}
+
+	if flagReader.dumpLumpsInfo != "" {
+		wl.DumpPAKEntriesToTextFile(flagReader.dumpLumpsInfo, pak.Entries)
+	}
+
+	if flagReader.extractWAD != "" {
+		fmt.Println("Extracting entries...")
+
+		err := pak.ExtractAll(flagReader.extractWAD)
+		if err != nil {
+			fmt.Println("[Error] Cannot extract entries - " + err.Error())
+		} else {
+			fmt.Println("Entries extracted successfully")
+		}
+	}
+
+	// embedded WADs go through the same actions as standalone ones
+	for _, entry := range pak.EmbeddedWADs() {
+		entryFolder := embeddedWADFolder(entry.Name())
+		if entryFolder == "" {
+			fmt.Println("[Warn] Skipping the embedded WAD with an invalid name", entry.Name())
+			continue
+		}
+		flags := flagReader.forEmbeddedWAD(entryFolder)
+
+		wadType, err := pak.EmbeddedWADType(entry)
+		if err != nil {
+			fmt.Println("[Warn]", err.Error())
+			continue
+		}
+
+		if wadType == wl.ArchiveTypeWAD2 {
+			wad2, err := pak.OpenEmbeddedWAD2(entry)
+			if err != nil {
+				fmt.Println(err.Error())
+				continue
+			}
+
+			processWAD2(wad2, flags)
+			continue
+		}
+
+		wad, err := pak.OpenEmbeddedWAD(entry)
+		if err != nil {
+			fmt.Println(err.Error())
+			continue
+		}
+
+		wad.ReadWADLumps()
+		printWADInfo(wad)
+		processSingleFileActions(wad, flags)
+	}
+}
+
+func processGRPFile(filePath string) {
+	grp := wl.GRPLoader{}
+
+	err := grp.OpenAndLoad(filePath)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	defer grp.Close()
+
+	fmt.Println("GRP Filename:", grp.GRPFilename)
+	fmt.Println("# Entries:", len(grp.Entries))
+	fmt.Println("--------------------------")
+
+	if flagReader.listEntries {
+		wl.PrintGRPEntries(grp.Entries)
+	}
+
+	if flagReader.dumpLumpsInfo != "" {
+		wl.DumpGRPEntriesToTextFile(flagReader.dumpLumpsInfo, grp.Entries)
+	}
+
+	if flagReader.extractWAD != "" {
+		fmt.Println("Extracting entries...")
+
+		err := grp.ExtractAll(flagReader.extractWAD)
+		if err != nil {
+			fmt.Println("[Error] Cannot extract entries - " + err.Error())
+		} else {
+			fmt.Println("Entries extracted successfully")
+		}
+	}
+
+	if flagReader.exportSprites != "" {
+		fmt.Println("Exporting ART tiles...")
+
+		var palette wl.Palette
+		if flagReader.importPalette != "" {
+			palette, err = wl.ImportPalette(flagReader.importPalette)
+		} else {
+			palette, err = grp.LoadPalette()
+		}
+
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+
+		for _, entry := range grp.ArtEntries() {
+			artData, err := grp.ReadEntryData(entry)
+			if err != nil {
+				fmt.Println("[Warn]", err.Error())
+				continue
+			}
+
+			tiles, err := wl.ParseArtTiles(artData)
+			if err != nil {
+				fmt.Println("[Warn] " + entry.Name() + " - " + err.Error())
+			}
+
+			err = wl.ExportAllArtTiles(tiles, palette, flagReader.exportSprites)
+			if err != nil {
+				fmt.Println("[Error] Cannot export ART tiles - " + err.Error())
+				return
+			}
+		}
+
+		fmt.Println("ART tiles exported successfully")
+	}
+}
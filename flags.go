@@ -3,43 +3,213 @@ package main
 import (
 	"flag"
 	"fmt"
+	"path"
+	"strings"
+
+	wl "github.com/segovia-no/wadtogo/wadloader"
 )
 
 type Flags struct {
 	WADFilenames      []string
 	printWADMusicInfo bool
 	printWADMapsInfo  bool
+	printIntegrity    bool
+	printMapStats     bool
+	printMusicStats   bool
+	printDehacked     bool
+	dehackedFile      string
+	printTextScreen   bool
+	listEntries       bool
 	dumpLumpsInfo     string
 	dumpWADMusicInfo  string
 	dumpWADMapsInfo   string
+	dumpIntegrity     string
 	exportMusic       string
+	exportPCSounds    string
+	renderMusic       string
+	musicSampleRate   int
+	musicMaxSeconds   float64
+	musicOPL3         bool
 	exportSprites     string
+	exportSpriteAnims string
+	exportAtlas       string
+	exportFonts       string
+	exportTextScreen  string
+	textScreenFont    string
+	atlasSize         int
+	atlasPadding      int
+	atlasExtrude      bool
+	extractWAD        string
+	exportMipLevels   bool
+	lightLevel        int
+	invulnerability   bool
+	exportColormap    string
+	importPalette     string
+	exportPalette     string
+	paletteFormat     string
+	paletteIndex      int
+	exportGenMIDI     string
+	importGenMIDI     string
+	importMusic       string
+	importSounds      string
+	soundSampleRate   int
+	convertPK3        string
+	convertWAD        string
+	convertPNG        bool
+	generatePalettes  bool
+	outputWAD         string
 	mergeWADS         bool
+	streamWADS        bool
+	workers           int
 }
 
 func (f *Flags) parseFlags() {
 	printWADMusicInfo := flag.Bool("musicinfo", false, "Print WAD's music info via console")
 	printWADMapsInfo := flag.Bool("mapsinfo", false, "Print WAD's maps info via console")
+	printTextScreen := flag.Bool("endoom", false, "Print the ENDOOM/ENDTEXT/ENDSTRF exit screen via console with ANSI colors")
+	printMusicStats := flag.Bool("music-analyze", false, "Print duration, tempo, channels, programs, notes and loop suitability of each song via console")
+	printDehacked := flag.Bool("dehacked", false, "Print the changes of the DEHACKED lump (or -deh patch) against vanilla Doom via console")
+	dehackedFile := flag.String("deh", "", "DeHackEd/BEX patch used instead of the DEHACKED lump by -dehacked and -mapstats")
+	printMapStats := flag.Bool("mapstats", false, "Print things, monsters health and linedef specials of each map via console")
+	listEntries := flag.Bool("list", false, "Print the archive's lumps/entries via console")
+	printIntegrity := flag.Bool("integrity", false, "Print WAD's lump integrity report via console")
 	dumpLumpsInfo := flag.String("lumpsinfo-dump", "", "Dump WAD's lumps info to file")
 	dumpWADMusicInfo := flag.String("musicinfo-dump", "", "Dump WAD's music info to file")
 	dumpWADMapsInfo := flag.String("mapsinfo-dump", "", "Dump WAD's maps info to file")
+	dumpIntegrity := flag.String("integrity-dump", "", "Dump WAD's lump integrity report to file")
 	exportMusic := flag.String("music-export", "", "Export WAD's music to folder")
+	renderMusic := flag.String("music-render", "", "Render WAD's MUS/MIDI music as WAV with the GENMIDI instruments on an emulated OPL to folder")
+	musicSampleRate := flag.Int("music-rate", wl.DefaultMusicSampleRate, "Sample rate of the -music-render WAV files")
+	musicMaxSeconds := flag.Float64("music-length", wl.DefaultMusicMaxSeconds, "Max seconds rendered per song by -music-render, 0 for no limit")
+	musicOPL3 := flag.Bool("music-opl3", false, "Render music with 18 OPL3 voices and stereo panning instead of 9 OPL2 voices")
+	exportPCSounds := flag.String("pcsound-export", "", "Export WAD's PC speaker sounds (DP*) as WAV to folder")
 	exportSprites := flag.String("sprite-export", "", "Export WAD's sprites to folder")
+	exportSpriteAnims := flag.String("sprite-anim", "", "Export WAD's sprites as animated GIFs per rotation and sprite sheets to folder")
+	exportFonts := flag.String("font-export", "", "Export the STCFN/FONTA/FONTB fonts as BMFont (.fnt + PNG pages) and glyph sheets to folder")
+	exportTextScreen := flag.String("endoom-export", "", "Export the ENDOOM/ENDTEXT/ENDSTRF exit screen as ANSI, HTML and PNG to folder")
+	textScreenFont := flag.String("endoom-font", "", "8 pixels wide VGA font (raw dump or PSF) used to draw the exit screen PNG")
+	exportAtlas := flag.String("atlas", "", "Pack sprites, patches, flats and textures into atlas PNG pages plus a JSON index in folder")
+	atlasSize := flag.Int("atlas-size", 2048, "Max width and height of the atlas pages, a power of two")
+	atlasPadding := flag.Int("atlas-padding", 0, "Empty pixels around every image of the atlas")
+	atlasExtrude := flag.Bool("atlas-extrude", false, "Fill the atlas padding repeating the image edges")
+	exportMipLevels := flag.Bool("mip-levels", false, "Export the 4 mip levels of Quake/Half-Life textures")
+	lightLevel := flag.Int("light-level", -1, "Render exported sprites and flats at a sector light level (0-255) using COLORMAP")
+	invulnerability := flag.Bool("invuln", false, "Render exported sprites and flats through the invulnerability colormap")
+	exportColormap := flag.String("colormap-export", "", "Export COLORMAP as a PNG strip, one row per colormap")
+	importPalette := flag.String("palette-import", "", "Use the palette from file (JASC/GIMP/ACT/raw/PNG swatch) instead of the archive one")
+	exportPalette := flag.String("palette-export", "", "Export a PLAYPAL palette to file, the format is taken from the extension")
+	paletteFormat := flag.String("palette-format", "", "Format used by -palette-export: jasc, gpl, act, raw or png")
+	paletteIndex := flag.Int("palette-index", 0, "PLAYPAL palette used by -palette-export")
+	extractWAD := flag.String("extract", "", "Extract WAD's lumps (or PAK/GRP entries) to folder, namespaces and maps as subfolders")
+	convertPK3 := flag.String("convert-pk3", "", "Convert the WAD into a PK3 file")
+	convertWAD := flag.String("convert-wad", "", "Convert the PK3 (or WAD) into a PWAD file")
+	convertPNG := flag.Bool("convert-png", false, "Convert sprites, patches and flats to PNG when using -convert-pk3")
+	exportGenMIDI := flag.String("genmidi-export", "", "Export the GENMIDI OPL instrument bank to file (.op2 bank or .json dump)")
+	importGenMIDI := flag.String("genmidi-import", "", "Import an .op2 or .json instrument bank as the GENMIDI lump of the -output-wad file")
+	importMusic := flag.String("music-import", "", "Convert MIDI files (comma separated) to MUS as D_ lumps of the -output-wad file")
+	importSounds := flag.String("sound-import", "", "Convert WAV files (comma separated) to DMX sounds as DS lumps of the -output-wad file")
+	soundSampleRate := flag.Int("sound-rate", wl.DefaultDMXSoundRate, "Sample rate of the -sound-import DMX sounds")
+	generatePalettes := flag.Bool("generate-palettes", false, "Generate PLAYPAL and COLORMAP from the base palette into the -output-wad file")
+	outputWAD := flag.String("output-wad", "", "PWAD file where generated lumps are written")
 	mergeWads := flag.Bool("mergewads", false, "Merge Multiple WADS into one")
+	workers := flag.Int("workers", 0, "Max parallel workers when decoding and exporting (0 = one per CPU)")
+	streamWads := flag.Bool("stream", false, "Read WAD's lumps from disk on demand instead of loading the whole file")
 
 	flag.Parse()
 
 	f.printWADMusicInfo = *printWADMusicInfo
 	f.printWADMapsInfo = *printWADMapsInfo
+	f.printIntegrity = *printIntegrity
+	f.printMapStats = *printMapStats
+	f.printMusicStats = *printMusicStats
+	f.printDehacked = *printDehacked
+	f.dehackedFile = *dehackedFile
+	f.printTextScreen = *printTextScreen
+	f.listEntries = *listEntries
 	f.dumpLumpsInfo = *dumpLumpsInfo
 	f.dumpWADMusicInfo = *dumpWADMusicInfo
 	f.dumpWADMapsInfo = *dumpWADMapsInfo
+	f.dumpIntegrity = *dumpIntegrity
 	f.exportMusic = *exportMusic
+	f.exportPCSounds = *exportPCSounds
+	f.renderMusic = *renderMusic
+	f.musicSampleRate = *musicSampleRate
+	f.musicMaxSeconds = *musicMaxSeconds
+	f.musicOPL3 = *musicOPL3
 	f.exportSprites = *exportSprites
+	f.exportSpriteAnims = *exportSpriteAnims
+	f.exportAtlas = *exportAtlas
+	f.exportFonts = *exportFonts
+	f.exportTextScreen = *exportTextScreen
+	f.textScreenFont = *textScreenFont
+	f.atlasSize = *atlasSize
+	f.atlasPadding = *atlasPadding
+	f.atlasExtrude = *atlasExtrude
+	f.extractWAD = *extractWAD
+	f.exportMipLevels = *exportMipLevels
+	f.lightLevel = *lightLevel
+	f.invulnerability = *invulnerability
+	f.exportColormap = *exportColormap
+	f.importPalette = *importPalette
+	f.exportPalette = *exportPalette
+	f.paletteFormat = *paletteFormat
+	f.paletteIndex = *paletteIndex
+	f.convertPK3 = *convertPK3
+	f.convertWAD = *convertWAD
+	f.convertPNG = *convertPNG
+	f.exportGenMIDI = *exportGenMIDI
+	f.importGenMIDI = *importGenMIDI
+	f.importMusic = *importMusic
+	f.importSounds = *importSounds
+	f.soundSampleRate = *soundSampleRate
+	f.generatePalettes = *generatePalettes
+	f.outputWAD = *outputWAD
 	f.mergeWADS = *mergeWads
+	f.streamWADS = *streamWads
+	f.workers = *workers
 	f.WADFilenames = flag.Args()
 }
 
 func (f *Flags) printFlags() {
 	fmt.Printf("%+v\n", f)
 }
+
+// subfolder for a WAD embedded in another archive, the entry name comes from the archive so it is
+// cleaned like the extracted entries. Empty when nothing is left of the name
+func embeddedWADFolder(entryName string) string {
+	entryName = strings.TrimSuffix(entryName, path.Ext(entryName))
+	return strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(entryName, "\\", "/")), "/")
+}
+
+// flags used for a WAD embedded in another archive: exports go into a subfolder named after
+// the entry and single file outputs are dropped so the parent archive ones aren't overwritten
+func (f Flags) forEmbeddedWAD(entryFolder string) Flags {
+	subfolder := func(folder string) string {
+		if folder == "" {
+			return ""
+		}
+		return strings.TrimSuffix(folder, "/") + "/" + entryFolder
+	}
+
+	f.exportMusic = subfolder(f.exportMusic)
+	f.exportPCSounds = subfolder(f.exportPCSounds)
+	f.renderMusic = subfolder(f.renderMusic)
+	f.exportSprites = subfolder(f.exportSprites)
+	f.exportSpriteAnims = subfolder(f.exportSpriteAnims)
+	f.exportAtlas = subfolder(f.exportAtlas)
+	f.exportFonts = subfolder(f.exportFonts)
+	f.exportTextScreen = subfolder(f.exportTextScreen)
+	f.extractWAD = subfolder(f.extractWAD)
+
+	f.dumpLumpsInfo = ""
+	f.dumpWADMusicInfo = ""
+	f.dumpWADMapsInfo = ""
+	f.dumpIntegrity = ""
+	f.exportColormap = ""
+	f.exportPalette = ""
+	f.exportGenMIDI = ""
+	f.convertPK3 = ""
+	f.convertWAD = ""
+
+	return f
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strings"
 
 	wl "github.com/segovia-no/wadtogo/wadloader"
 )
@@ -10,6 +13,12 @@ import (
 var flagReader Flags
 var wads []wl.WADLoader
 
+// generated lumps from every processed file end up here, written to -output-wad at the end
+var outputWAD = wl.WADWriter{WadType: "PWAD"}
+
+// -genmidi-import bank, also used to render the music of WADs without GENMIDI
+var importedGenMIDI *wl.GenMIDI
+
 func main() {
 	fmt.Println("WADToGo - Another WAD Tool")
 	fmt.Println("--------------------------")
@@ -21,9 +30,43 @@ func main() {
 		fmt.Println("Cannot perform actions without at least one WAD file")
 	}
 
-	wads = make([]wl.WADLoader, wadcount)
-	for idx, filepath := range flagReader.WADFilenames {
-		wads[idx] = loadWAD(filepath)
+	if flagReader.importGenMIDI != "" {
+		importGenMIDI()
+	}
+
+	if flagReader.importMusic != "" {
+		importMusic()
+	}
+
+	if flagReader.importSounds != "" {
+		importSounds()
+	}
+
+	wads = make([]wl.WADLoader, 0, wadcount)
+	for _, wadPath := range flagReader.WADFilenames {
+		archiveType, err := wl.DetectArchiveType(wadPath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+
+		// non Doom archives are handled on their own
+		if archiveType == wl.ArchiveTypeWAD2 {
+			processWAD2File(wadPath)
+			continue
+		}
+
+		if archiveType == wl.ArchiveTypePAK {
+			processPAKFile(wadPath)
+			continue
+		}
+
+		if archiveType == wl.ArchiveTypeGRP {
+			processGRPFile(wadPath)
+			continue
+		}
+
+		wads = append(wads, loadWAD(wadPath, archiveType))
 	}
 
 	if flagReader.mergeWADS {
@@ -32,25 +75,81 @@ func main() {
 	}
 
 	for _, wad := range wads {
-		processSingleFileActions(&wad)
+		processSingleFileActions(&wad, flagReader)
+		wad.Close()
 	}
 
+	writeOutputWAD()
 }
 
-func loadWAD(filePath string) wl.WADLoader {
-	wad := wl.WADLoader{}
-	wad.OpenAndLoad(filePath)
+func writeOutputWAD() {
+	if outputWAD.LumpCount() < 1 {
+		return
+	}
 
-	fmt.Println("WAD Filename:", wad.WADFilename)
-	fmt.Println("WAD Type:", string(wad.WADHeader.WadType[:]))
-	fmt.Println("# Lumps:", wad.WADHeader.LumpEntries)
+	if flagReader.outputWAD == "" {
+		fmt.Println("[Warn] Generated lumps were discarded, use -output-wad to save them")
+		return
+	}
+
+	err := outputWAD.WriteFile(flagReader.outputWAD)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("[Info]", outputWAD.LumpCount(), "lumps written into", flagReader.outputWAD)
+}
+
+func loadWAD(filePath string, archiveType string) wl.WADLoader {
+	wad := wl.WADLoader{Workers: flagReader.workers}
+
+	if archiveType == wl.ArchiveTypePK3 {
+		err := wad.OpenAndLoadPK3(filePath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	} else if flagReader.streamWADS {
+		err := wad.OpenAndStream(filePath)
+		if err != nil {
+			fmt.Println(err.Error())
+			os.Exit(1)
+		}
+	} else {
+		wad.OpenAndLoad(filePath)
+	}
 
-	fmt.Println("--------------------------")
 	wad.ReadWADLumps()
+	printWADInfo(&wad)
 
 	return wad
 }
 
+func printWADInfo(wad *wl.WADLoader) {
+	fmt.Println("WAD Filename:", wad.WADFilename)
+	fmt.Println("WAD Type:", string(bytes.Trim(wad.WADHeader.WadType[:], "\x00")))
+	fmt.Println("# Lumps:", wad.WADHeader.LumpEntries)
+
+	// the game profile is selected from the identified game
+	identity, err := wad.DetectProfile()
+	if err == nil {
+		if identity.IsIWAD {
+			fmt.Println("Game:", identity.String(), "- identified by", identity.Method)
+			if identity.MD5 != "" {
+				fmt.Println("MD5:", identity.MD5, "SHA-1:", identity.SHA1)
+			}
+		} else {
+			fmt.Println("Made for:", identity.String(), "- guessed from the", identity.Method)
+		}
+		fmt.Println("Game profile:", wad.Profile.Name)
+	} else {
+		wad.Profile = &wl.DoomProfile
+	}
+
+	fmt.Println("--------------------------")
+}
+
 func processMergeWads() {
 	if len(wads) < 2 {
 		fmt.Println("Cannot merge wads with less than two WAD files")
@@ -60,46 +159,144 @@ func processMergeWads() {
 	//TODO: Merge wads
 }
 
-func processSingleFileActions(wad *wl.WADLoader) {
-	if flagReader.printWADMusicInfo || flagReader.dumpWADMusicInfo != "" || flagReader.exportMusic != "" {
+func processSingleFileActions(wad *wl.WADLoader, flags Flags) {
+	if flags.printWADMusicInfo || flags.dumpWADMusicInfo != "" || flags.exportMusic != "" || flags.renderMusic != "" || flags.printMusicStats {
 		musicLumps, _ := wad.GetMusicLumps()
 		wad.Music = append(wad.Music, musicLumps...)
 	}
 
-	if flagReader.printWADMapsInfo || flagReader.dumpWADMapsInfo != "" {
+	if flags.printWADMusicInfo || flags.exportPCSounds != "" {
+		pcSounds, _ := wad.GetPCSpeakerSounds()
+		wad.PCSounds = append(wad.PCSounds, pcSounds...)
+	}
+
+	if flags.printWADMapsInfo || flags.dumpWADMapsInfo != "" || flags.printMapStats {
 		wad.LoadMaps()
 	}
 
-	if flagReader.exportSprites != "" {
-		wad.LoadPalettes()
+	exportsGraphics := flags.exportSprites != "" || flags.exportSpriteAnims != "" || flags.exportAtlas != "" ||
+		flags.exportFonts != ""
+
+	if exportsGraphics {
+		loadPalettes(wad, flags)
 		wad.LoadGraphics()
+
+		err := wad.LoadLooseGraphics()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	if flags.exportAtlas != "" {
+		err := wad.LoadTextures()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	if (exportsGraphics && (flags.lightLevel >= 0 || flags.invulnerability)) || flags.exportColormap != "" {
+		if len(wad.Palettes) < 1 {
+			loadPalettes(wad, flags)
+		}
+
+		err := wad.LoadColormaps()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	if exportsGraphics && len(wad.Colormaps) > 0 {
+		var err error
+		if flags.invulnerability {
+			err = wad.SetRenderColormap(wl.ColormapInvulnerability)
+		} else if flags.lightLevel >= 0 {
+			err = wad.SetLightLevel(flags.lightLevel)
+		}
+
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+	}
+
+	if flags.convertPK3 != "" && flags.convertPNG && len(wad.Palettes) < 1 {
+		loadPalettes(wad, flags)
 	}
 
 	// Command execution
-	if flagReader.dumpLumpsInfo != "" {
-		wl.DumpLumpsToTextFile(flagReader.dumpLumpsInfo, wad.WADLumps)
+	if flags.listEntries {
+		wl.PrintLumps(wad.WADLumps)
+	}
+
+	if flags.dumpLumpsInfo != "" {
+		wl.DumpLumpsToTextFile(flags.dumpLumpsInfo, wad.WADLumps)
+	}
+
+	if flags.printIntegrity || flags.dumpIntegrity != "" {
+		report, err := wad.CheckLumpIntegrity()
+		if err != nil {
+			fmt.Println(err.Error())
+		} else {
+			if flags.printIntegrity {
+				wl.PrintLumpIntegrity(report)
+			}
+
+			if flags.dumpIntegrity != "" {
+				wl.DumpLumpIntegrityToTextFile(flags.dumpIntegrity, report)
+			}
+		}
 	}
 
-	if flagReader.printWADMusicInfo {
+	if flags.printWADMusicInfo {
 		wl.PrintSongNames(wad.Music)
+
+		if len(wad.PCSounds) > 0 {
+			wl.PrintPCSpeakerSounds(wad.PCSounds)
+		}
+	}
+
+	if flags.printMusicStats {
+		wl.PrintSongAnalysis(wad.AnalyzeSongs())
 	}
 
-	if flagReader.dumpWADMusicInfo != "" {
-		wl.DumpSongNamesToTextFile(flagReader.dumpWADMusicInfo, wad.Music)
+	if flags.dumpWADMusicInfo != "" {
+		wl.DumpSongNamesToTextFile(flags.dumpWADMusicInfo, wad.Music)
 	}
 
-	if flagReader.printWADMapsInfo {
+	if flags.printWADMapsInfo {
 		wl.PrintMapNames(wad.Maps)
 	}
 
-	if flagReader.dumpWADMapsInfo != "" {
-		wl.DumpMapNamesToTextFile(flagReader.dumpWADMapsInfo, wad.Maps)
+	if flags.dumpWADMapsInfo != "" {
+		wl.DumpMapNamesToTextFile(flags.dumpWADMapsInfo, wad.Maps)
+	}
+
+	if flags.printDehacked {
+		patch, err := loadDehacked(wad, flags)
+		if err != nil {
+			fmt.Println(err.Error())
+		} else {
+			wl.PrintDehackedReport(patch)
+		}
 	}
 
-	if flagReader.exportSprites != "" {
+	if flags.printMapStats {
+		// the things changed by the patch are counted with their new numbers and hit points
+		if patch, err := loadDehacked(wad, flags); err == nil {
+			profile := wad.Profile.WithDehacked(patch)
+			wad.Profile = &profile
+		}
+
+		stats := make([]wl.MapStats, 0, len(wad.Maps))
+		for _, m := range wad.Maps {
+			stats = append(stats, wad.GetMapStats(m))
+		}
+		wl.PrintMapStats(stats, wad.Profile.Name)
+	}
+
+	if flags.exportSprites != "" {
 		fmt.Println("Exporting sprites...")
 
-		err := wad.ExportAllSprites(flagReader.exportSprites)
+		err := wad.ExportAllSprites(flags.exportSprites)
 		if err != nil {
 			fmt.Println("[Error] Cannot export sprites - " + err.Error())
 		}
@@ -107,13 +304,350 @@ func processSingleFileActions(wad *wl.WADLoader) {
 		fmt.Println("Sprites exported successfully")
 	}
 
-	if flagReader.exportMusic != "" {
+	if flags.exportAtlas != "" {
+		fmt.Println("Packing atlas...")
+
+		options := wl.AtlasOptions{
+			MaxPageSize: flags.atlasSize,
+			Padding:     flags.atlasPadding,
+			Extrude:     flags.atlasExtrude,
+		}
+
+		err := wad.ExportAtlas(flags.exportAtlas, options)
+		if err != nil {
+			fmt.Println("[Error] Cannot export the atlas - " + err.Error())
+		} else {
+			fmt.Println("Atlas exported successfully")
+		}
+	}
+
+	if flags.exportFonts != "" {
+		fmt.Println("Exporting fonts...")
+
+		err := wad.ExportFonts(flags.exportFonts)
+		if err != nil {
+			fmt.Println(err.Error())
+		} else {
+			fmt.Println("Fonts exported successfully")
+		}
+	}
+
+	if flags.printTextScreen {
+		screens, err := wad.DetectTextScreens()
+		if err != nil {
+			fmt.Println(err.Error())
+		}
+
+		for _, screen := range screens {
+			fmt.Print(screen.ANSI())
+		}
+	}
+
+	if flags.exportTextScreen != "" {
+		exportTextScreens(wad, flags)
+	}
+
+	if flags.exportSpriteAnims != "" {
+		fmt.Println("Exporting sprite animations...")
+
+		err := wad.ExportSpriteAnimations(flags.exportSpriteAnims)
+		if err != nil {
+			fmt.Println("[Error] Cannot export sprite animations - " + err.Error())
+		} else {
+			fmt.Println("Sprite animations exported successfully")
+		}
+	}
+
+	if flags.generatePalettes {
+		generatePalettes(wad, flags)
+	}
+
+	if flags.exportPalette != "" {
+		exportPalette(wad, flags)
+	}
+
+	if flags.exportGenMIDI != "" {
+		exportGenMIDI(wad, flags)
+	}
+
+	if flags.exportColormap != "" {
+		err := wad.ExportColormaps(flags.exportColormap)
+		if err != nil {
+			fmt.Println("[Error] Cannot export the colormaps - " + err.Error())
+		} else {
+			fmt.Println("Colormaps exported successfully")
+		}
+	}
+
+	if flags.extractWAD != "" {
+		fmt.Println("Extracting lumps...")
+
+		wadFS, err := wad.FS()
+		if err == nil {
+			err = wl.ExtractFS(wadFS, flags.extractWAD)
+		}
+
+		if err != nil {
+			fmt.Println("[Error] Cannot extract lumps - " + err.Error())
+		} else {
+			fmt.Println("Lumps extracted successfully")
+		}
+	}
+
+	if flags.convertPK3 != "" {
+		fmt.Println("Converting to PK3...")
+
+		err := wad.ConvertToPK3(flags.convertPK3, flags.convertPNG)
+		if err != nil {
+			fmt.Println("[Error] Cannot convert to PK3 - " + err.Error())
+		} else {
+			fmt.Println("PK3 written to", flags.convertPK3)
+		}
+	}
+
+	if flags.convertWAD != "" {
+		fmt.Println("Converting to WAD...")
+
+		err := wad.ConvertToWAD(flags.convertWAD)
+		if err != nil {
+			fmt.Println("[Error] Cannot convert to WAD - " + err.Error())
+		} else {
+			fmt.Println("WAD written to", flags.convertWAD)
+		}
+	}
+
+	if flags.exportMusic != "" {
 		fmt.Println("Exporting songs...")
 
-		err := wad.ExportAllSongs(flagReader.exportMusic)
+		err := wad.ExportAllSongs(flags.exportMusic)
 		if err != nil {
 			fmt.Println("[Error] Cannot export songs - " + err.Error())
 		}
 		fmt.Println("Songs exported successfully")
 	}
+
+	if flags.renderMusic != "" {
+		renderMusic(wad, flags)
+	}
+
+	if flags.exportPCSounds != "" {
+		fmt.Println("Exporting PC speaker sounds...")
+
+		err := wad.ExportAllPCSpeakerSounds(flags.exportPCSounds)
+		if err != nil {
+			fmt.Println("[Error] Cannot export PC speaker sounds - " + err.Error())
+		} else {
+			fmt.Println("PC speaker sounds exported successfully")
+		}
+	}
+}
+
+// the built-in font is used unless -endoom-font gives a real VGA one
+func exportTextScreens(wad *wl.WADLoader, flags Flags) {
+	font := wl.BuiltinBitmapFont()
+
+	if flags.textScreenFont != "" {
+		loadedFont, err := wl.LoadBitmapFont(flags.textScreenFont)
+		if err != nil {
+			fmt.Println(err.Error())
+			return
+		}
+		font = loadedFont
+	}
+
+	fmt.Println("Exporting exit screens...")
+
+	err := wad.ExportTextScreens(flags.exportTextScreen, font)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("Exit screens exported successfully")
+}
+
+// the base palette is the imported one or the first PLAYPAL palette of the WAD
+func generatePalettes(wad *wl.WADLoader, flags Flags) {
+	if len(wad.Palettes) < 1 {
+		loadPalettes(wad, flags)
+	}
+
+	basePalette := wad.Palettes[0]
+
+	fmt.Println("Generating PLAYPAL and COLORMAP...")
+
+	outputWAD.AddLump("PLAYPAL", wl.PalettesToLumpData(wl.GeneratePlaypal(basePalette)))
+	outputWAD.AddLump("COLORMAP", wl.ColormapsToLumpData(wl.GenerateColormaps(basePalette)))
+}
+
+func exportPalette(wad *wl.WADLoader, flags Flags) {
+	if len(wad.Palettes) < 1 {
+		loadPalettes(wad, flags)
+	}
+
+	if flags.paletteIndex < 0 || flags.paletteIndex >= len(wad.Palettes) {
+		fmt.Println("[Error] The WAD has no palette", flags.paletteIndex)
+		return
+	}
+
+	paletteFormat := flags.paletteFormat
+	if paletteFormat == "" {
+		paletteFormat = wl.PaletteFormatFromFilename(flags.exportPalette)
+	}
+
+	err := wl.ExportPalette(wad.Palettes[flags.paletteIndex], flags.exportPalette, paletteFormat)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("Palette exported successfully")
+}
+
+func exportGenMIDI(wad *wl.WADLoader, flags Flags) {
+	genMIDI, err := wad.LoadGenMIDI()
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	err = wl.ExportGenMIDI(genMIDI, flags.exportGenMIDI, wl.GenMIDIFormatFromFilename(flags.exportGenMIDI))
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	fmt.Println("GENMIDI exported successfully")
+}
+
+func importGenMIDI() {
+	genMIDI, err := wl.ImportGenMIDI(flagReader.importGenMIDI)
+	if err != nil {
+		fmt.Println(err.Error())
+		return
+	}
+
+	importedGenMIDI = &genMIDI
+	outputWAD.AddLump("GENMIDI", genMIDI.Bytes())
+}
+
+// the -deh patch replaces the DEHACKED lump of the WAD
+func loadDehacked(wad *wl.WADLoader, flags Flags) (wl.DehackedPatch, error) {
+	if flags.dehackedFile != "" {
+		return wl.LoadDehackedFile(flags.dehackedFile)
+	}
+	return wad.LoadDehacked()
+}
+
+// the lump is named after the file, with the prefix added when it's missing: e1m1.mid is D_E1M1
+func importLumpName(filename string, prefix string) string {
+	name := strings.ToUpper(strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename)))
+	if !strings.HasPrefix(name, prefix) {
+		name = prefix + name
+	}
+	if len(name) > 8 {
+		name = name[:8]
+	}
+	return name
+}
+
+func importMusic() {
+	for _, filename := range strings.Split(flagReader.importMusic, ",") {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Println("[Error] Cannot read " + filename + " - " + err.Error())
+			continue
+		}
+
+		musData, report, err := wl.ConvertMIDIToMUS(data)
+		for _, problem := range report {
+			fmt.Println("[Warn] " + filename + ": " + problem)
+		}
+		if err != nil {
+			fmt.Println("[Error] Cannot convert " + filename + " - " + err.Error())
+			continue
+		}
+
+		lumpName := importLumpName(filename, "D_")
+		outputWAD.AddLump(lumpName, musData)
+		fmt.Println("[Info] " + filename + " converted to MUS as " + lumpName)
+	}
+}
+
+func importSounds() {
+	for _, filename := range strings.Split(flagReader.importSounds, ",") {
+		data, err := os.ReadFile(filename)
+		if err != nil {
+			fmt.Println("[Error] Cannot read " + filename + " - " + err.Error())
+			continue
+		}
+
+		audio, err := wl.DecodeWAV(data)
+		if err != nil {
+			fmt.Println("[Error] Cannot read " + filename + " - " + err.Error())
+			continue
+		}
+
+		soundData, err := wl.EncodeDMXSound(audio, flagReader.soundSampleRate)
+		if err != nil {
+			fmt.Println("[Error] Cannot convert " + filename + " - " + err.Error())
+			continue
+		}
+
+		lumpName := importLumpName(filename, "DS")
+		outputWAD.AddLump(lumpName, soundData)
+		fmt.Println("[Info] " + filename + " converted to a DMX sound as " + lumpName)
+	}
+}
+
+// PWADs without their own GENMIDI are played with the -genmidi-import bank
+func renderMusic(wad *wl.WADLoader, flags Flags) {
+	genMIDI, err := wad.LoadGenMIDI()
+	if err != nil {
+		if importedGenMIDI == nil {
+			fmt.Println("[Error] Cannot render music without GENMIDI, use -genmidi-import to give a bank - " + err.Error())
+			return
+		}
+		genMIDI = *importedGenMIDI
+	}
+
+	options := wl.MusicRenderOptions{
+		SampleRate: flags.musicSampleRate,
+		MaxSeconds: flags.musicMaxSeconds,
+		OPL3:       flags.musicOPL3,
+	}
+
+	fmt.Println("Rendering songs...")
+
+	err = wad.RenderAllSongs(flags.renderMusic, &genMIDI, options)
+	if err != nil {
+		fmt.Println("[Error] Cannot render songs - " + err.Error())
+		return
+	}
+
+	fmt.Println("Songs rendered successfully")
+}
+
+// -palette-import replaces the first PLAYPAL palette, the one used by exports and generators
+// the imported palette replaces the first PLAYPAL one, WADs without PLAYPAL get it as their only palette
+func loadPalettes(wad *wl.WADLoader, flags Flags) {
+	if flags.importPalette == "" {
+		wad.LoadPalettes()
+		return
+	}
+
+	palette, err := wl.ImportPalette(flags.importPalette)
+	if err != nil {
+		fmt.Println(err.Error())
+		wad.LoadPalettes()
+		return
+	}
+
+	palettes, err := wad.DetectPalettes()
+	if err != nil || len(palettes) < 1 {
+		palettes = []wl.Palette{palette}
+	}
+
+	palettes[0] = palette
+	wad.Palettes = palettes
 }
@@ -0,0 +1,51 @@
+package wadloader
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+)
+
+const (
+	ArchiveTypeWAD  = "WAD"
+	ArchiveTypePK3  = "PK3"
+	ArchiveTypeWAD2 = "WAD2"
+	ArchiveTypePAK  = "PAK"
+	ArchiveTypeGRP  = "GRP"
+)
+
+// archive type is guessed from the file signature, not from the extension
+func DetectArchiveType(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", errors.New("[Error] DetectArchiveType: Couldn't open the file - " + err.Error())
+	}
+
+	defer file.Close()
+
+	signature := make([]byte, 12)
+	n, err := io.ReadFull(file, signature)
+	if err != nil && n < 4 {
+		return "", errors.New("[Error] DetectArchiveType: Couldn't read the file signature - " + err.Error())
+	}
+
+	return detectArchiveTypeFromSignature(signature[:n])
+}
+
+func detectArchiveTypeFromSignature(signature []byte) (string, error) {
+	switch {
+	case bytes.HasPrefix(signature, []byte("IWAD")), bytes.HasPrefix(signature, []byte("PWAD")):
+		return ArchiveTypeWAD, nil
+	case bytes.HasPrefix(signature, []byte("PK\x03\x04")), bytes.HasPrefix(signature, []byte("PK\x05\x06")):
+		return ArchiveTypePK3, nil
+	case bytes.HasPrefix(signature, []byte("WAD2")), bytes.HasPrefix(signature, []byte("WAD3")):
+		return ArchiveTypeWAD2, nil
+	case bytes.HasPrefix(signature, []byte("PACK")):
+		return ArchiveTypePAK, nil
+	case bytes.HasPrefix(signature, []byte("KenSilverman")):
+		return ArchiveTypeGRP, nil
+	}
+
+	return "", errors.New("[Error] DetectArchiveType: Unknown archive signature")
+}
@@ -0,0 +1,173 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+)
+
+// Build engine ART tile sets: a 16 bytes header, the tile widths, heights and
+// animation fields, then the pixels of every tile stored column by column
+type ArtTile struct {
+	Number    int
+	Width     int16
+	Height    int16
+	PicAnm    uint32
+	PixelData []byte
+}
+
+type ArtHeader struct {
+	Version        uint32
+	TileCount      uint32
+	LocalTileStart uint32
+	LocalTileEnd   uint32
+}
+
+// the tile center offsets are stored as signed bytes inside the animation field
+func (t ArtTile) Offsets() (int8, int8) {
+	return int8(t.PicAnm >> 8), int8(t.PicAnm >> 16)
+}
+
+func ParseArtTiles(data []byte) ([]ArtTile, error) {
+	var header ArtHeader
+
+	reader := bytes.NewReader(data)
+
+	err := binary.Read(reader, binary.LittleEndian, &header)
+	if err != nil {
+		return nil, errors.New("[Error] ParseArtTiles: Cannot read the ART header - " + err.Error())
+	}
+
+	if header.Version != 1 || header.LocalTileEnd < header.LocalTileStart {
+		return nil, errors.New("[Error] ParseArtTiles: Invalid ART header")
+	}
+
+	// the TileCount field isn't reliable, the local range is what the file holds. It's checked
+	// before converting and multiplying, the range can overflow an int on 32 bits builds
+	tileRange := uint64(header.LocalTileEnd-header.LocalTileStart) + 1
+
+	if tileRange > uint64((len(data)-16)/8) {
+		return nil, errors.New("[Error] ParseArtTiles: The tile tables exceed the file data")
+	}
+
+	tileCount := int(tileRange)
+
+	widths := make([]int16, tileCount)
+	heights := make([]int16, tileCount)
+	picAnms := make([]uint32, tileCount)
+
+	binary.Read(reader, binary.LittleEndian, &widths)
+	binary.Read(reader, binary.LittleEndian, &heights)
+	binary.Read(reader, binary.LittleEndian, &picAnms)
+
+	tiles := make([]ArtTile, 0, tileCount)
+	pixelOffset := 16 + tileCount*8
+
+	for i := 0; i < tileCount; i++ {
+		tile := ArtTile{
+			Number: int(header.LocalTileStart) + i,
+			Width:  widths[i],
+			Height: heights[i],
+			PicAnm: picAnms[i],
+		}
+
+		if tile.Width <= 0 || tile.Height <= 0 {
+			continue
+		}
+
+		pixelCount := int(tile.Width) * int(tile.Height)
+		if pixelCount > len(data)-pixelOffset {
+			return tiles, errors.New("[Error] ParseArtTiles: Tile " + strconv.Itoa(tile.Number) + " exceeds the file data")
+		}
+
+		tile.PixelData = data[pixelOffset : pixelOffset+pixelCount]
+		pixelOffset += pixelCount
+
+		tiles = append(tiles, tile)
+	}
+
+	return tiles, nil
+}
+
+// index 255 is the transparent color in Build tiles
+func ArtTileToImage(tile ArtTile, palette Palette) *image.RGBA {
+	width := int(tile.Width)
+	height := int(tile.Height)
+
+	tileImg := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			colorIdx := tile.PixelData[x*height+y]
+			if colorIdx == 255 {
+				continue
+			}
+
+			pixelColor := palette[colorIdx]
+			tileImg.Set(x, y, color.RGBA{pixelColor.Red, pixelColor.Green, pixelColor.Blue, 255})
+		}
+	}
+
+	return tileImg
+}
+
+func ExportArtTile(tile ArtTile, palette Palette, outputFolder string) error {
+	tileImg := ArtTileToImage(tile, palette)
+	tileName := fmt.Sprintf("TILE%04d", tile.Number)
+
+	tileFile, err := os.Create(outputFolder + "/" + tileName + ".png")
+	if err != nil {
+		return errors.New("[Error] ExportArtTile: Cannot create the target file for the tile - " + tileName + " - " + err.Error())
+	}
+
+	defer tileFile.Close()
+	png.Encode(tileFile, tileImg)
+
+	return nil
+}
+
+func ExportAllArtTiles(tiles []ArtTile, palette Palette, outputFolder string) error {
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	for _, tile := range tiles {
+		exportErr := ExportArtTile(tile, palette, outputFolder)
+		if exportErr != nil {
+			return errors.New("[Error] ExportAllArtTiles: Cannot export tile - " + exportErr.Error())
+		}
+	}
+
+	return nil
+}
+
+// VGA palettes store 6 bit components (0-63), like Build's PALETTE.DAT
+func PaletteFromVGAData(data []byte) (Palette, error) {
+	var palette Palette
+
+	if len(data) < 768 {
+		return palette, errors.New("[Error] PaletteFromVGAData: A palette needs 768 bytes")
+	}
+
+	for i := 0; i < 256; i++ {
+		palette[i] = PaletteColor{
+			Red:   vgaToRGBComponent(data[i*3]),
+			Green: vgaToRGBComponent(data[i*3+1]),
+			Blue:  vgaToRGBComponent(data[i*3+2]),
+		}
+	}
+
+	return palette, nil
+}
+
+func vgaToRGBComponent(value byte) byte {
+	value &= 63
+	return value<<2 | value>>4
+}
@@ -0,0 +1,338 @@
+package wadloader
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"os"
+	"sort"
+	"strconv"
+)
+
+const (
+	AtlasKindSprite  = "sprite"
+	AtlasKindPatch   = "patch"
+	AtlasKindFlat    = "flat"
+	AtlasKindTexture = "texture"
+)
+
+type AtlasOptions struct {
+	// max width and height of a page, pages are shrunk to the smallest power of two that fits
+	MaxPageSize int
+	// empty pixels around every image
+	Padding int
+	// fills the padding repeating the image edges, avoids bleeding when filtering
+	Extrude bool
+}
+
+type AtlasImage struct {
+	Name       string
+	Kind       string
+	Image      *image.RGBA
+	LeftOffset int
+	TopOffset  int
+}
+
+type AtlasEntry struct {
+	Name       string `json:"name"`
+	Kind       string `json:"kind"`
+	Page       int    `json:"page"`
+	X          int    `json:"x"`
+	Y          int    `json:"y"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	LeftOffset int    `json:"leftOffset"`
+	TopOffset  int    `json:"topOffset"`
+}
+
+type AtlasIndex struct {
+	Pages   []string     `json:"pages"`
+	Entries []AtlasEntry `json:"entries"`
+}
+
+// skyline bottom-left packing: every page keeps the top edge of the packed
+// images as segments and each image goes where it ends the lowest
+type skylineSegment struct {
+	x, y, width int
+}
+
+type skylinePage struct {
+	size     int
+	skyline  []skylineSegment
+	usedSize image.Point
+}
+
+func newSkylinePage(size int) *skylinePage {
+	return &skylinePage{size: size, skyline: []skylineSegment{{0, 0, size}}}
+}
+
+// returns the position and false when the rectangle doesn't fit anywhere
+func (sp *skylinePage) findPosition(width int, height int) (int, int, int, bool) {
+	bestIdx, bestX, bestY := -1, 0, sp.size
+
+	for idx, segment := range sp.skyline {
+		if segment.x+width > sp.size {
+			break
+		}
+
+		// the rectangle rests on the highest segment it spans
+		y, remaining := 0, width
+		for spanIdx := idx; remaining > 0; spanIdx++ {
+			if sp.skyline[spanIdx].y > y {
+				y = sp.skyline[spanIdx].y
+			}
+			remaining -= sp.skyline[spanIdx].width
+		}
+
+		if y+height <= sp.size && y < bestY {
+			bestIdx, bestX, bestY = idx, segment.x, y
+		}
+	}
+
+	return bestX, bestY, bestIdx, bestIdx >= 0
+}
+
+func (sp *skylinePage) place(x int, y int, width int, height int, segmentIdx int) {
+	newSegment := skylineSegment{x, y + height, width}
+
+	// segments covered by the new one are removed or cut
+	rest := sp.skyline[segmentIdx:]
+	var kept []skylineSegment
+	for _, segment := range rest {
+		segmentEnd := segment.x + segment.width
+		if segmentEnd <= x+width {
+			continue
+		}
+		if segment.x < x+width {
+			segment.width = segmentEnd - (x + width)
+			segment.x = x + width
+		}
+		kept = append(kept, segment)
+	}
+
+	skyline := append([]skylineSegment{}, sp.skyline[:segmentIdx]...)
+	skyline = append(skyline, newSegment)
+	skyline = append(skyline, kept...)
+
+	// neighbours at the same height are merged
+	merged := skyline[:1]
+	for _, segment := range skyline[1:] {
+		last := &merged[len(merged)-1]
+		if last.y == segment.y {
+			last.width += segment.width
+			continue
+		}
+		merged = append(merged, segment)
+	}
+
+	sp.skyline = merged
+
+	if x+width > sp.usedSize.X {
+		sp.usedSize.X = x + width
+	}
+	if y+height > sp.usedSize.Y {
+		sp.usedSize.Y = y + height
+	}
+}
+
+func PackAtlas(images []AtlasImage, options AtlasOptions) ([]*image.RGBA, []AtlasEntry, error) {
+	// pages are grown to powers of two, so the max size has to be one
+	if options.MaxPageSize < 1 || options.MaxPageSize&(options.MaxPageSize-1) != 0 {
+		return nil, nil, errors.New("[Error] PackAtlas: The page size must be a power of two")
+	}
+
+	if options.Padding < 0 {
+		return nil, nil, errors.New("[Error] PackAtlas: The padding cannot be negative")
+	}
+
+	// taller images first packs tighter
+	order := make([]int, len(images))
+	for idx := range order {
+		order[idx] = idx
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return images[order[i]].Image.Bounds().Dy() > images[order[j]].Image.Bounds().Dy()
+	})
+
+	var pages []*skylinePage
+	entries := make([]AtlasEntry, 0, len(images))
+	placedImages := make([]int, 0, len(images))
+
+	for _, imageIdx := range order {
+		atlasImage := images[imageIdx]
+		width := atlasImage.Image.Bounds().Dx() + options.Padding*2
+		height := atlasImage.Image.Bounds().Dy() + options.Padding*2
+
+		if width > options.MaxPageSize || height > options.MaxPageSize {
+			fmt.Println("[Warn] PackAtlas: " + atlasImage.Name + " is bigger than an atlas page, skipping")
+			continue
+		}
+
+		pageIdx, x, y := -1, 0, 0
+		for idx, page := range pages {
+			posX, posY, segmentIdx, fits := page.findPosition(width, height)
+			if fits {
+				page.place(posX, posY, width, height, segmentIdx)
+				pageIdx, x, y = idx, posX, posY
+				break
+			}
+		}
+
+		if pageIdx < 0 {
+			page := newSkylinePage(options.MaxPageSize)
+			page.place(0, 0, width, height, 0)
+			pages = append(pages, page)
+			pageIdx = len(pages) - 1
+		}
+
+		entries = append(entries, AtlasEntry{
+			Name:       atlasImage.Name,
+			Kind:       atlasImage.Kind,
+			Page:       pageIdx,
+			X:          x + options.Padding,
+			Y:          y + options.Padding,
+			Width:      atlasImage.Image.Bounds().Dx(),
+			Height:     atlasImage.Image.Bounds().Dy(),
+			LeftOffset: atlasImage.LeftOffset,
+			TopOffset:  atlasImage.TopOffset,
+		})
+		placedImages = append(placedImages, imageIdx)
+	}
+
+	pageImages := make([]*image.RGBA, len(pages))
+	for idx, page := range pages {
+		pageImages[idx] = image.NewRGBA(image.Rect(0, 0, nextPowerOfTwo(page.usedSize.X), nextPowerOfTwo(page.usedSize.Y)))
+	}
+
+	for entryIdx, entry := range entries {
+		source := images[placedImages[entryIdx]].Image
+		target := pageImages[entry.Page]
+		targetRect := image.Rect(entry.X, entry.Y, entry.X+entry.Width, entry.Y+entry.Height)
+
+		draw.Draw(target, targetRect, source, source.Bounds().Min, draw.Src)
+
+		if options.Extrude {
+			extrudeEdges(target, targetRect, options.Padding)
+		}
+	}
+
+	return pageImages, entries, nil
+}
+
+// the padding around rect takes the color of the closest image pixel
+func extrudeEdges(target *image.RGBA, rect image.Rectangle, padding int) {
+	for y := rect.Min.Y - padding; y < rect.Max.Y+padding; y++ {
+		for x := rect.Min.X - padding; x < rect.Max.X+padding; x++ {
+			if image.Pt(x, y).In(rect) {
+				continue
+			}
+
+			sourceX := clampInt(x, rect.Min.X, rect.Max.X-1)
+			sourceY := clampInt(y, rect.Min.Y, rect.Max.Y-1)
+			target.Set(x, y, target.At(sourceX, sourceY))
+		}
+	}
+}
+
+func clampInt(value int, low int, high int) int {
+	if value < low {
+		return low
+	}
+	if value > high {
+		return high
+	}
+	return value
+}
+
+func nextPowerOfTwo(value int) int {
+	size := 1
+	for size < value {
+		size <<= 1
+	}
+	return size
+}
+
+// sprites, patches, loose graphics, flats and composite textures, each one loaded beforehand
+func (wl *WADLoader) atlasImages() []AtlasImage {
+	palette := wl.renderPalette()
+	var images []AtlasImage
+
+	for _, sprite := range wl.Sprites {
+		images = append(images, AtlasImage{sprite.Name, AtlasKindSprite, PatchToImage(sprite, palette), int(sprite.LeftOffset), int(sprite.TopOffset)})
+	}
+
+	for _, patch := range wl.Patches {
+		images = append(images, AtlasImage{patch.Name, AtlasKindPatch, PatchToImage(patch, palette), int(patch.LeftOffset), int(patch.TopOffset)})
+	}
+
+	for _, graphic := range wl.Graphics {
+		images = append(images, AtlasImage{graphic.Name, AtlasKindPatch, PatchToImage(graphic, palette), int(graphic.LeftOffset), int(graphic.TopOffset)})
+	}
+
+	for _, screen := range wl.Screens {
+		images = append(images, AtlasImage{screen.Name, AtlasKindPatch, RawScreenToImage(screen, palette), 0, 0})
+	}
+
+	for _, flat := range wl.Flats {
+		images = append(images, AtlasImage{flat.Name, AtlasKindFlat, FlatToImage(flat, palette), 0, 0})
+	}
+
+	patchCache := make(map[string]*Patch)
+	for _, texture := range wl.Textures {
+		textureImg, err := wl.ComposeTexture(texture, palette, patchCache)
+		if err != nil {
+			fmt.Println("[Warn] " + err.Error())
+			continue
+		}
+		images = append(images, AtlasImage{texture.Name, AtlasKindTexture, textureImg, 0, 0})
+	}
+
+	return images
+}
+
+// pages are written as atlas_N.png next to atlas.json
+func (wl *WADLoader) ExportAtlas(outputFolder string, options AtlasOptions) error {
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	pages, entries, err := PackAtlas(wl.atlasImages(), options)
+	if err != nil {
+		return err
+	}
+
+	index := AtlasIndex{Entries: entries}
+
+	for idx, page := range pages {
+		pageName := "atlas_" + strconv.Itoa(idx) + ".png"
+
+		pageFile, err := os.Create(outputFolder + "/" + pageName)
+		if err != nil {
+			return errors.New("[Error] ExportAtlas: Cannot create the atlas page - " + err.Error())
+		}
+
+		err = png.Encode(pageFile, page)
+		pageFile.Close()
+		if err != nil {
+			return errors.New("[Error] ExportAtlas: Cannot encode the atlas page - " + err.Error())
+		}
+
+		index.Pages = append(index.Pages, pageName)
+	}
+
+	indexData, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return errors.New("[Error] ExportAtlas: Cannot encode the atlas index - " + err.Error())
+	}
+
+	err = os.WriteFile(outputFolder+"/atlas.json", indexData, 0644)
+	if err != nil {
+		return errors.New("[Error] ExportAtlas: Cannot write the atlas index - " + err.Error())
+	}
+
+	return nil
+}
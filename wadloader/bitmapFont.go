@@ -0,0 +1,79 @@
+package wadloader
+
+import (
+	"encoding/binary"
+	"errors"
+	"os"
+)
+
+// 8 pixels wide text mode fonts, one byte per glyph row with the leftmost pixel in the highest bit
+type BitmapFont struct {
+	Height int
+	Glyphs [256][]byte
+}
+
+// code page 437 as unicode, the control codes are shown as the glyphs the VGA draws for them
+var cp437Runes = [256]rune{
+	' ', '☺', '☻', '♥', '♦', '♣', '♠', '•', '◘', '○', '◙', '♂', '♀', '♪', '♫', '☼',
+	'►', '◄', '↕', '‼', '¶', '§', '▬', '↨', '↑', '↓', '→', '←', '∟', '↔', '▲', '▼',
+	' ', '!', '"', '#', '$', '%', '&', '\'', '(', ')', '*', '+', ',', '-', '.', '/',
+	'0', '1', '2', '3', '4', '5', '6', '7', '8', '9', ':', ';', '<', '=', '>', '?',
+	'@', 'A', 'B', 'C', 'D', 'E', 'F', 'G', 'H', 'I', 'J', 'K', 'L', 'M', 'N', 'O',
+	'P', 'Q', 'R', 'S', 'T', 'U', 'V', 'W', 'X', 'Y', 'Z', '[', '\\', ']', '^', '_',
+	'`', 'a', 'b', 'c', 'd', 'e', 'f', 'g', 'h', 'i', 'j', 'k', 'l', 'm', 'n', 'o',
+	'p', 'q', 'r', 's', 't', 'u', 'v', 'w', 'x', 'y', 'z', '{', '|', '}', '~', '⌂',
+	'Ç', 'ü', 'é', 'â', 'ä', 'à', 'å', 'ç', 'ê', 'ë', 'è', 'ï', 'î', 'ì', 'Ä', 'Å',
+	'É', 'æ', 'Æ', 'ô', 'ö', 'ò', 'û', 'ù', 'ÿ', 'Ö', 'Ü', '¢', '£', '¥', '₧', 'ƒ',
+	'á', 'í', 'ó', 'ú', 'ñ', 'Ñ', 'ª', 'º', '¿', '⌐', '¬', '½', '¼', '¡', '«', '»',
+	'░', '▒', '▓', '│', '┤', '╡', '╢', '╖', '╕', '╣', '║', '╗', '╝', '╜', '╛', '┐',
+	'└', '┴', '┬', '├', '─', '┼', '╞', '╟', '╚', '╔', '╩', '╦', '╠', '═', '╬', '╧',
+	'╨', '╤', '╥', '╙', '╘', '╒', '╓', '╫', '╪', '┘', '┌', '█', '▄', '▌', '▐', '▀',
+	'α', 'ß', 'Γ', 'π', 'Σ', 'σ', 'µ', 'τ', 'Φ', 'Θ', 'Ω', 'δ', '∞', 'φ', 'ε', '∩',
+	'≡', '±', '≥', '≤', '⌠', '⌡', '÷', '≈', '°', '∙', '·', '√', 'ⁿ', '²', '■', '\u00A0',
+}
+
+// the IBM VGA font can't be shipped, the built-in one is a freely licensed look-alike
+// (see builtinFont.go). -endoom-font loads a real 8x16 VGA font (raw or PSF)
+func BuiltinBitmapFont() *BitmapFont {
+	font := &BitmapFont{Height: len(builtinFontGlyphs[0])}
+
+	for code := range font.Glyphs {
+		glyph := builtinFontGlyphs[code]
+		font.Glyphs[code] = glyph[:]
+	}
+
+	return font
+}
+
+// raw dumps (256 glyphs of 8 to 32 rows, like the 4096 bytes 8x16 VGA dumps) and PSF1/PSF2 console fonts
+func LoadBitmapFont(filename string) (*BitmapFont, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.New("[Error] LoadBitmapFont: Cannot read the font file - " + err.Error())
+	}
+
+	glyphsStart, height := 0, 0
+
+	switch {
+	case len(data) >= 4 && data[0] == 0x36 && data[1] == 0x04:
+		glyphsStart, height = 4, int(data[3])
+	case len(data) >= 32 && binary.LittleEndian.Uint32(data) == 0x864AB572:
+		if binary.LittleEndian.Uint32(data[28:]) > 8 {
+			return nil, errors.New("[Error] LoadBitmapFont: Only 8 pixels wide PSF2 fonts are supported")
+		}
+		glyphsStart, height = int(binary.LittleEndian.Uint32(data[8:])), int(binary.LittleEndian.Uint32(data[24:]))
+	case len(data)%256 == 0:
+		height = len(data) / 256
+	}
+
+	if height < 8 || height > 32 || glyphsStart+256*height > len(data) {
+		return nil, errors.New("[Error] LoadBitmapFont: Unknown font format, expected a raw 8xN dump or a PSF font")
+	}
+
+	font := &BitmapFont{Height: height}
+	for code := range font.Glyphs {
+		font.Glyphs[code] = data[glyphsStart+code*height : glyphsStart+(code+1)*height]
+	}
+
+	return font, nil
+}
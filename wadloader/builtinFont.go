@@ -0,0 +1,310 @@
+package wadloader
+
+// The built-in 8x16 font used for the exit screen PNG, drawn from DejaVu Sans Mono Bold
+// at 16 pixels with some glyphs touched up by hand and the shade, block and box drawing
+// characters built to fill the whole cell. DejaVu is derived from Bitstream Vera:
+//
+// Copyright (c) 2003 by Bitstream, Inc. All Rights Reserved.
+// Bitstream Vera is a trademark of Bitstream, Inc.
+// DejaVu changes are in public domain.
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of the fonts accompanying this license ("Fonts") and associated
+// documentation files (the "Font Software"), to reproduce and distribute the
+// Font Software, including without limitation the rights to use, copy, merge,
+// publish, distribute, and/or sell copies of the Font Software, and to permit
+// persons to whom the Font Software is furnished to do so, subject to the
+// following conditions:
+//
+// The above copyright and trademark notices and this permission notice shall
+// be included in all copies of one or more of the Font Software typefaces.
+//
+// The Font Software may be modified, altered, or added to, and in particular
+// the designs of glyphs or characters in the Fonts may be modified and
+// additional glyphs or characters may be added to the Fonts, only if the fonts
+// are renamed to names not containing either the words "Bitstream" or the word
+// "Vera".
+//
+// This License becomes null and void to the extent applicable to Fonts or Font
+// Software that has been modified and is distributed under the "Bitstream
+// Vera" names.
+//
+// The Font Software may be sold as part of a larger software package but no
+// copy of one or more of the Font Software typefaces may be sold by itself.
+//
+// THE FONT SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS
+// OR IMPLIED, INCLUDING BUT NOT LIMITED TO ANY WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT OF COPYRIGHT, PATENT,
+// TRADEMARK, OR OTHER RIGHT. IN NO EVENT SHALL BITSTREAM OR THE GNOME
+// FOUNDATION BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER LIABILITY, INCLUDING
+// ANY GENERAL, SPECIAL, INDIRECT, INCIDENTAL, OR CONSEQUENTIAL DAMAGES,
+// WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM, OUT OF
+// THE USE OR INABILITY TO USE THE FONT SOFTWARE OR FROM OTHER DEALINGS IN THE
+// FONT SOFTWARE.
+//
+// Except as contained in this notice, the names of Gnome, the Gnome
+// Foundation, and Bitstream Inc., shall not be used in advertising or
+// otherwise to promote the sale, use or other dealings in this Font Software
+// without prior written authorization from the Gnome Foundation or Bitstream
+// Inc., respectively. For further information, contact: fonts at gnome dot
+// org.
+
+// one byte per row, the leftmost pixel in the highest bit
+var builtinFontGlyphs = [256][16]byte{
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x00
+	{0x00, 0x00, 0x7C, 0x82, 0xAA, 0x82, 0xAA, 0x92, 0x82, 0x7C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x01 ☺
+	{0x00, 0x00, 0x7C, 0xFE, 0xD6, 0xFE, 0xD6, 0xEE, 0xFE, 0x7C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x02 ☻
+	{0x00, 0x00, 0x00, 0xCC, 0xFE, 0xFE, 0xFE, 0xFC, 0x78, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x03 ♥
+	{0x00, 0x00, 0x00, 0x00, 0x30, 0x78, 0xFC, 0xFC, 0x78, 0x30, 0x20, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x04 ♦
+	{0x00, 0x00, 0x00, 0x30, 0x78, 0x78, 0x30, 0xFC, 0xFE, 0xCC, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x05 ♣
+	{0x00, 0x00, 0x00, 0x00, 0x30, 0x78, 0x78, 0xFC, 0xFC, 0xCC, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x06 ♠
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x38, 0x7C, 0x7C, 0x7C, 0x38, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x07 •
+	{0x00, 0x00, 0xFE, 0xFE, 0xFE, 0xC6, 0x82, 0x82, 0x82, 0xC6, 0xFE, 0xFE, 0xFE, 0x00, 0x00, 0x00}, // 0x08 ◘
+	{0x00, 0x00, 0x00, 0x00, 0x38, 0x44, 0x82, 0x82, 0x82, 0x44, 0x38, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x09 ○
+	{0x00, 0x00, 0xFE, 0xFE, 0xC6, 0xBA, 0x7C, 0x7C, 0x7C, 0xBA, 0xC6, 0xFE, 0xFE, 0x00, 0x00, 0x00}, // 0x0A ◙
+	{0x00, 0x00, 0x1E, 0x06, 0x0A, 0x78, 0xCC, 0xCC, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x0B ♂
+	{0x00, 0x00, 0x78, 0xCC, 0xCC, 0xCC, 0x78, 0x30, 0xFC, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x0C ♀
+	{0x00, 0x00, 0x3C, 0x36, 0x32, 0x30, 0x30, 0x30, 0xF0, 0xF0, 0x60, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x0D ♪
+	{0x00, 0x00, 0x7E, 0x66, 0x7E, 0x66, 0x66, 0x66, 0x6E, 0xEE, 0xEC, 0xC0, 0x00, 0x00, 0x00, 0x00}, // 0x0E ♫
+	{0x00, 0x00, 0x00, 0x00, 0x10, 0xBA, 0x44, 0xC6, 0x44, 0xBA, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x0F ☼
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x80, 0xF0, 0xFC, 0xF0, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x10 ►
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x3E, 0xFE, 0x3E, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x11 ◄
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x78, 0x30, 0x30, 0x30, 0x78, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x12 ↕
+	{0x00, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x00, 0xCC, 0xCC, 0x00, 0x00, 0x00, 0x00}, // 0x13 ‼
+	{0x00, 0x00, 0x7E, 0xF6, 0xF6, 0xF6, 0x76, 0x16, 0x16, 0x16, 0x16, 0x16, 0x00, 0x00, 0x00, 0x00}, // 0x14 ¶
+	{0x00, 0x00, 0x78, 0xC0, 0xE0, 0x78, 0xDC, 0xCC, 0xEC, 0x78, 0x1C, 0x0C, 0x78, 0x00, 0x00, 0x00}, // 0x15 §
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFE, 0xFE, 0xFE, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x16 ▬
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x78, 0x30, 0x78, 0x78, 0x30, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x17 ↨
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x78, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x18 ↑
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x30, 0x30, 0x30, 0x78, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x19 ↓
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x08, 0x0C, 0xFE, 0x0C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x1A →
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0xC0, 0xFE, 0xC0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x1B ←
+	{0x00, 0x00, 0x00, 0x00, 0x80, 0x80, 0x80, 0x80, 0x80, 0xFC, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x1C ∟
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x48, 0xCC, 0xFE, 0xCC, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x1D ↔
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x38, 0x38, 0x7C, 0x7C, 0xFE, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0x1E ▲
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xFE, 0xFE, 0x7C, 0x7C, 0x38, 0x38, 0x10, 0x00, 0x00, 0x00, 0x00}, // 0x1F ▼
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x20
+	{0x00, 0x00, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x21 !
+	{0x00, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x22 "
+	{0x00, 0x00, 0x6C, 0x6C, 0xFE, 0x6C, 0x6C, 0x6C, 0xFE, 0x6C, 0x6C, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x23 #
+	{0x00, 0x30, 0x7C, 0xC6, 0xC0, 0xC0, 0x7C, 0x06, 0x06, 0xC6, 0x7C, 0x30, 0x30, 0x00, 0x00, 0x00}, // 0x24 $
+	{0x00, 0x00, 0x00, 0x00, 0xC2, 0xC6, 0x0C, 0x18, 0x30, 0x60, 0xC6, 0x86, 0x00, 0x00, 0x00, 0x00}, // 0x25 %
+	{0x00, 0x00, 0x38, 0x6C, 0x6C, 0x38, 0x76, 0xDC, 0xCC, 0xCC, 0xCC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0x26 &
+	{0x00, 0x00, 0x30, 0x30, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x27 '
+	{0x00, 0x18, 0x30, 0x30, 0x60, 0x60, 0x60, 0x60, 0x60, 0x60, 0x30, 0x30, 0x18, 0x00, 0x00, 0x00}, // 0x28 (
+	{0x00, 0x60, 0x30, 0x30, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x30, 0x30, 0x60, 0x00, 0x00, 0x00}, // 0x29 )
+	{0x00, 0x00, 0x00, 0x00, 0x10, 0x92, 0x7C, 0x38, 0x7C, 0x92, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x2A *
+	{0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x30, 0xFE, 0xFE, 0x30, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x2B +
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x20, 0x40, 0x00, 0x00}, // 0x2C ,
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0x78, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x2D -
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x2E .
+	{0x00, 0x00, 0x06, 0x0C, 0x0C, 0x18, 0x18, 0x30, 0x60, 0x60, 0xC0, 0xC0, 0x00, 0x00, 0x00, 0x00}, // 0x2F /
+	{0x00, 0x00, 0x38, 0x6C, 0xC6, 0xC6, 0xD6, 0xD6, 0xC6, 0xC6, 0x6C, 0x38, 0x00, 0x00, 0x00, 0x00}, // 0x30 0
+	{0x00, 0x00, 0x30, 0x70, 0xF0, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x31 1
+	{0x00, 0x00, 0x7C, 0xC6, 0x06, 0x0C, 0x18, 0x30, 0x60, 0xC0, 0xC6, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0x32 2
+	{0x00, 0x00, 0x7C, 0xC6, 0x06, 0x06, 0x3C, 0x06, 0x06, 0x06, 0xC6, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x33 3
+	{0x00, 0x00, 0x0C, 0x1C, 0x3C, 0x6C, 0xCC, 0xFE, 0x0C, 0x0C, 0x0C, 0x1E, 0x00, 0x00, 0x00, 0x00}, // 0x34 4
+	{0x00, 0x00, 0xFE, 0xC0, 0xC0, 0xC0, 0xFC, 0x06, 0x06, 0x06, 0xC6, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x35 5
+	{0x00, 0x00, 0x38, 0x60, 0xC0, 0xC0, 0xFC, 0xC6, 0xC6, 0xC6, 0xC6, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x36 6
+	{0x00, 0x00, 0xFE, 0xC6, 0x06, 0x0C, 0x18, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x37 7
+	{0x00, 0x00, 0x7C, 0xC6, 0xC6, 0xC6, 0x7C, 0xC6, 0xC6, 0xC6, 0xC6, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x38 8
+	{0x00, 0x00, 0x7C, 0xC6, 0xC6, 0xC6, 0xC6, 0x7E, 0x06, 0x06, 0x0C, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x39 9
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x00, 0x00, 0x00, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x3A :
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x00, 0x00, 0x00, 0x30, 0x30, 0x20, 0x40, 0x00, 0x00}, // 0x3B ;
+	{0x00, 0x00, 0x00, 0x00, 0x0C, 0x18, 0x30, 0x60, 0x30, 0x18, 0x0C, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x3C <
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFE, 0xFE, 0x00, 0xFE, 0xFE, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x3D =
+	{0x00, 0x00, 0x00, 0x00, 0x60, 0x30, 0x18, 0x0C, 0x18, 0x30, 0x60, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x3E >
+	{0x00, 0x00, 0x7C, 0xC6, 0xC6, 0x0C, 0x18, 0x18, 0x18, 0x00, 0x18, 0x18, 0x00, 0x00, 0x00, 0x00}, // 0x3F ?
+	{0x00, 0x00, 0x7C, 0xC6, 0xC6, 0xDE, 0xDE, 0xDE, 0xDC, 0xC0, 0xC0, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x40 @
+	{0x00, 0x00, 0x38, 0x38, 0x38, 0x38, 0x6C, 0x6C, 0x7C, 0x6C, 0xC6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x41 A
+	{0x00, 0x00, 0xFC, 0xC6, 0xC6, 0xC6, 0xFC, 0xC6, 0xC6, 0xC6, 0xC6, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x42 B
+	{0x00, 0x00, 0x3C, 0x62, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0x62, 0x3C, 0x00, 0x00, 0x00, 0x00}, // 0x43 C
+	{0x00, 0x00, 0xF8, 0xCC, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xCC, 0xF8, 0x00, 0x00, 0x00, 0x00}, // 0x44 D
+	{0x00, 0x00, 0xFE, 0xC0, 0xC0, 0xC0, 0xFC, 0xC0, 0xC0, 0xC0, 0xC0, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0x45 E
+	{0x00, 0x00, 0xFE, 0xC0, 0xC0, 0xC0, 0xFC, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0x00, 0x00, 0x00, 0x00}, // 0x46 F
+	{0x00, 0x00, 0x3C, 0x62, 0xC0, 0xC0, 0xC0, 0xCE, 0xC6, 0xC6, 0x66, 0x3E, 0x00, 0x00, 0x00, 0x00}, // 0x47 G
+	{0x00, 0x00, 0xC6, 0xC6, 0xC6, 0xC6, 0xFE, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x48 H
+	{0x00, 0x00, 0xFC, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x49 I
+	{0x00, 0x00, 0x1E, 0x06, 0x06, 0x06, 0x06, 0x06, 0x06, 0x06, 0x86, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x4A J
+	{0x00, 0x00, 0xC6, 0xCC, 0xD8, 0xF0, 0xF0, 0xF8, 0xD8, 0xCC, 0xCC, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x4B K
+	{0x00, 0x00, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0x4C L
+	{0x00, 0x00, 0xC6, 0xEE, 0xFE, 0xFE, 0xD6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x4D M
+	{0x00, 0x00, 0xC6, 0xE6, 0xF6, 0xFE, 0xDE, 0xCE, 0xC6, 0xC6, 0xC6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x4E N
+	{0x00, 0x00, 0x38, 0x6C, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x6C, 0x38, 0x00, 0x00, 0x00, 0x00}, // 0x4F O
+	{0x00, 0x00, 0xFC, 0xC6, 0xC6, 0xC6, 0xCE, 0xFC, 0xC0, 0xC0, 0xC0, 0xC0, 0x00, 0x00, 0x00, 0x00}, // 0x50 P
+	{0x00, 0x00, 0x38, 0x6C, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x6C, 0x3C, 0x0C, 0x04, 0x00, 0x00}, // 0x51 Q
+	{0x00, 0x00, 0xFC, 0xC6, 0xC6, 0xC6, 0xFC, 0xD8, 0xCC, 0xCC, 0xC6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x52 R
+	{0x00, 0x00, 0x7C, 0xC6, 0xC0, 0x60, 0x38, 0x0C, 0x06, 0x06, 0xC6, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x53 S
+	{0x00, 0x00, 0xFC, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x54 T
+	{0x00, 0x00, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x55 U
+	{0x00, 0x00, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x6C, 0x6C, 0x38, 0x38, 0x10, 0x00, 0x00, 0x00, 0x00}, // 0x56 V
+	{0x00, 0x00, 0xC6, 0xC6, 0xC6, 0xC6, 0xD6, 0xD6, 0xD6, 0xFE, 0xEE, 0x6C, 0x00, 0x00, 0x00, 0x00}, // 0x57 W
+	{0x00, 0x00, 0xC6, 0x6C, 0x6C, 0x38, 0x38, 0x38, 0x38, 0x6C, 0x6C, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x58 X
+	{0x00, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0x78, 0x30, 0x30, 0x30, 0x30, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x59 Y
+	{0x00, 0x00, 0xFE, 0x06, 0x0C, 0x1C, 0x18, 0x30, 0x60, 0x60, 0xC0, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0x5A Z
+	{0x00, 0x3C, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x3C, 0x00, 0x00, 0x00}, // 0x5B [
+	{0x00, 0x00, 0xC0, 0x60, 0x60, 0x30, 0x30, 0x18, 0x0C, 0x0C, 0x06, 0x06, 0x00, 0x00, 0x00, 0x00}, // 0x5C \
+	{0x00, 0x78, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x78, 0x00, 0x00, 0x00}, // 0x5D ]
+	{0x00, 0x10, 0x38, 0x6C, 0xC6, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x5E ^
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFE, 0x00}, // 0x5F _
+	{0x00, 0x60, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x60 `
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0x0C, 0x7C, 0xCC, 0xCC, 0xCC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0x61 a
+	{0x00, 0xC0, 0xC0, 0xC0, 0xC0, 0xF8, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xF8, 0x00, 0x00, 0x00, 0x00}, // 0x62 b
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0xCC, 0xC0, 0xC0, 0xC0, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x63 c
+	{0x00, 0x0C, 0x0C, 0x0C, 0x0C, 0x7C, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x64 d
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0xCC, 0xCC, 0xFC, 0xC0, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x65 e
+	{0x00, 0x1C, 0x30, 0x30, 0x30, 0xFC, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x66 f
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x7C, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x0C, 0x4C, 0x38, 0x00}, // 0x67 g
+	{0x00, 0xC0, 0xC0, 0xC0, 0xC0, 0xF8, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x00, 0x00, 0x00, 0x00}, // 0x68 h
+	{0x00, 0x30, 0x30, 0x00, 0x00, 0xF0, 0x30, 0x30, 0x30, 0x30, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x69 i
+	{0x00, 0x18, 0x18, 0x00, 0x00, 0x78, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0xF0, 0x00}, // 0x6A j
+	{0x00, 0xC0, 0xC0, 0xC0, 0xC0, 0xC8, 0xD8, 0xF0, 0xF0, 0xD8, 0xD8, 0xCC, 0x00, 0x00, 0x00, 0x00}, // 0x6B k
+	{0x00, 0xF0, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x1E, 0x00, 0x00, 0x00, 0x00}, // 0x6C l
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xD8, 0xFE, 0xD6, 0xD6, 0xD6, 0xD6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x6D m
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xF8, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x00, 0x00, 0x00, 0x00}, // 0x6E n
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x6F o
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xF8, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xF8, 0xC0, 0xC0, 0xC0, 0x00}, // 0x70 p
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x7C, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x0C, 0x0C, 0x0C, 0x00}, // 0x71 q
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xDC, 0xEC, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0x00, 0x00, 0x00, 0x00}, // 0x72 r
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0xCC, 0xC0, 0x78, 0x0C, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x73 s
+	{0x00, 0x00, 0x00, 0x30, 0x30, 0xFC, 0x30, 0x30, 0x30, 0x30, 0x30, 0x1C, 0x00, 0x00, 0x00, 0x00}, // 0x74 t
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x75 u
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0x78, 0x78, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x76 v
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xC6, 0xC6, 0xD6, 0xD6, 0xD6, 0xFE, 0x6C, 0x00, 0x00, 0x00, 0x00}, // 0x77 w
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xCC, 0xCC, 0x78, 0x30, 0x78, 0xCC, 0xCC, 0x00, 0x00, 0x00, 0x00}, // 0x78 x
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xCC, 0xCC, 0x78, 0x78, 0x78, 0x30, 0x30, 0x30, 0x60, 0xE0, 0x00}, // 0x79 y
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xFC, 0x0C, 0x18, 0x30, 0x60, 0xC0, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x7A z
+	{0x00, 0x1C, 0x30, 0x30, 0x30, 0x30, 0x30, 0xE0, 0x30, 0x30, 0x30, 0x30, 0x1C, 0x00, 0x00, 0x00}, // 0x7B {
+	{0x00, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00, 0x00}, // 0x7C |
+	{0x00, 0xE0, 0x30, 0x30, 0x30, 0x30, 0x30, 0x1C, 0x30, 0x30, 0x30, 0x30, 0xE0, 0x00, 0x00, 0x00}, // 0x7D }
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x76, 0xDC, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0x7E ~
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x48, 0x84, 0x84, 0x84, 0x84, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x7F
+	{0x00, 0x00, 0x3C, 0x62, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0x62, 0x3C, 0x10, 0x08, 0x38, 0x00}, // 0x80 Ç
+	{0x00, 0x00, 0x00, 0xCC, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x81 ü
+	{0x00, 0x00, 0x18, 0x30, 0x00, 0x78, 0xCC, 0xCC, 0xFC, 0xC0, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x82 é
+	{0x00, 0x00, 0x30, 0x48, 0x00, 0x78, 0x0C, 0x7C, 0xCC, 0xCC, 0xCC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0x83 â
+	{0x00, 0x00, 0x00, 0xCC, 0x00, 0x78, 0x0C, 0x7C, 0xCC, 0xCC, 0xCC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0x84 ä
+	{0x00, 0x00, 0x60, 0x30, 0x00, 0x78, 0x0C, 0x7C, 0xCC, 0xCC, 0xCC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0x85 à
+	{0x00, 0x30, 0x48, 0x30, 0x00, 0x78, 0x0C, 0x7C, 0xCC, 0xCC, 0xCC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0x86 å
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0xCC, 0xC0, 0xC0, 0xC0, 0xCC, 0x78, 0x30, 0x18, 0x70, 0x00}, // 0x87 ç
+	{0x00, 0x00, 0x30, 0x48, 0x00, 0x78, 0xCC, 0xCC, 0xFC, 0xC0, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x88 ê
+	{0x00, 0x00, 0x00, 0xCC, 0x00, 0x78, 0xCC, 0xCC, 0xFC, 0xC0, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x89 ë
+	{0x00, 0x00, 0x60, 0x30, 0x00, 0x78, 0xCC, 0xCC, 0xFC, 0xC0, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x8A è
+	{0x00, 0x00, 0x00, 0xCC, 0x00, 0xF0, 0x30, 0x30, 0x30, 0x30, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x8B ï
+	{0x00, 0x00, 0x30, 0x48, 0x00, 0xF0, 0x30, 0x30, 0x30, 0x30, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x8C î
+	{0x00, 0x00, 0x60, 0x30, 0x00, 0xF0, 0x30, 0x30, 0x30, 0x30, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x8D ì
+	{0x6C, 0x6C, 0x38, 0x38, 0x38, 0x38, 0x6C, 0x6C, 0x7C, 0x6C, 0xC6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x8E Ä
+	{0x38, 0x28, 0x38, 0x38, 0x38, 0x38, 0x6C, 0x6C, 0x7C, 0x6C, 0xC6, 0xC6, 0x00, 0x00, 0x00, 0x00}, // 0x8F Å
+	{0x18, 0x30, 0xFE, 0xC0, 0xC0, 0xC0, 0xFC, 0xC0, 0xC0, 0xC0, 0xC0, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0x90 É
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x6C, 0x16, 0x7E, 0xD0, 0xD0, 0xD2, 0x6C, 0x00, 0x00, 0x00, 0x00}, // 0x91 æ
+	{0x00, 0x00, 0x7E, 0x78, 0x58, 0x58, 0xDE, 0xD8, 0xF8, 0x98, 0x98, 0x9E, 0x00, 0x00, 0x00, 0x00}, // 0x92 Æ
+	{0x00, 0x00, 0x30, 0x48, 0x00, 0x78, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x93 ô
+	{0x00, 0x00, 0x00, 0xCC, 0x00, 0x78, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x94 ö
+	{0x00, 0x00, 0x60, 0x30, 0x00, 0x78, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0x95 ò
+	{0x00, 0x00, 0x30, 0x48, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x96 û
+	{0x00, 0x00, 0x60, 0x30, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x97 ù
+	{0x00, 0x00, 0x00, 0xCC, 0x00, 0xCC, 0xCC, 0x78, 0x78, 0x78, 0x30, 0x30, 0x30, 0x60, 0xE0, 0x00}, // 0x98 ÿ
+	{0x6C, 0x6C, 0x38, 0x6C, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x6C, 0x38, 0x00, 0x00, 0x00, 0x00}, // 0x99 Ö
+	{0x6C, 0x6C, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0xC6, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0x9A Ü
+	{0x00, 0x00, 0x00, 0x10, 0x10, 0x78, 0xD4, 0xD0, 0xD0, 0xD0, 0x54, 0x38, 0x10, 0x10, 0x00, 0x00}, // 0x9B ¢
+	{0x00, 0x00, 0x38, 0x64, 0x60, 0x60, 0x60, 0xF8, 0x60, 0x60, 0x60, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0x9C £
+	{0x00, 0x00, 0x86, 0xCC, 0xCC, 0xCC, 0xFE, 0x30, 0xFE, 0x30, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0x9D ¥
+	{0x00, 0x00, 0x80, 0xD0, 0x70, 0x7E, 0x78, 0xDC, 0x9E, 0x12, 0x1A, 0x1E, 0x00, 0x00, 0x00, 0x00}, // 0x9E ₧
+	{0x00, 0x00, 0x1C, 0x38, 0x30, 0x7C, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0xF0, 0xE0, 0x00}, // 0x9F ƒ
+	{0x00, 0x00, 0x18, 0x30, 0x00, 0x78, 0x0C, 0x7C, 0xCC, 0xCC, 0xCC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0xA0 á
+	{0x00, 0x00, 0x18, 0x30, 0x00, 0xF0, 0x30, 0x30, 0x30, 0x30, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0xA1 í
+	{0x00, 0x00, 0x18, 0x30, 0x00, 0x78, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0xA2 ó
+	{0x00, 0x00, 0x18, 0x30, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0xA3 ú
+	{0x00, 0x00, 0x64, 0x98, 0x00, 0xF8, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x00, 0x00, 0x00, 0x00}, // 0xA4 ñ
+	{0x76, 0xDC, 0xE6, 0xE6, 0xE6, 0xF6, 0xD6, 0xD6, 0xDE, 0xCE, 0xCE, 0xCE, 0x00, 0x00, 0x00, 0x00}, // 0xA5 Ñ
+	{0x00, 0x00, 0x38, 0x04, 0x3C, 0x44, 0x3C, 0x00, 0x3C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xA6 ª
+	{0x00, 0x00, 0x38, 0x44, 0x44, 0x44, 0x38, 0x00, 0x7C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xA7 º
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x00, 0x30, 0x30, 0x30, 0x60, 0xC0, 0xC8, 0x70, 0x00}, // 0xA8 ¿
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFC, 0xFC, 0x80, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xA9 ⌐
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFE, 0xFE, 0x06, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xAA ¬
+	{0x00, 0xC0, 0x40, 0x40, 0x40, 0xE0, 0x0C, 0x70, 0xBC, 0x04, 0x08, 0x10, 0x3C, 0x00, 0x00, 0x00}, // 0xAB ½
+	{0x00, 0xC0, 0x40, 0x40, 0x40, 0xE0, 0x0C, 0x70, 0x98, 0x38, 0x68, 0x7C, 0x08, 0x00, 0x00, 0x00}, // 0xAC ¼
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x00, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x30, 0x00}, // 0xAD ¡
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x24, 0x6C, 0xD8, 0xD8, 0x6C, 0x24, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xAE «
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x90, 0xD8, 0x6C, 0x6C, 0xD8, 0x90, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xAF »
+	{0x88, 0x22, 0x88, 0x22, 0x88, 0x22, 0x88, 0x22, 0x88, 0x22, 0x88, 0x22, 0x88, 0x22, 0x88, 0x22}, // 0xB0 ░
+	{0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55, 0xAA, 0x55}, // 0xB1 ▒
+	{0x77, 0xDD, 0x77, 0xDD, 0x77, 0xDD, 0x77, 0xDD, 0x77, 0xDD, 0x77, 0xDD, 0x77, 0xDD, 0x77, 0xDD}, // 0xB2 ▓
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xB3 │
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xF0, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xB4 ┤
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xF0, 0x10, 0xF0, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xB5 ╡
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xF8, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xB6 ╢
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xB7 ╖
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0x00, 0xF0, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xB8 ╕
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xF8, 0x28, 0xF8, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xB9 ╣
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xBA ║
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0x00, 0xF8, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xBB ╗
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xF8, 0x28, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xBC ╝
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xF8, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xBD ╜
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xF0, 0x10, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xBE ╛
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xF0, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xBF ┐
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xC0 └
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xC1 ┴
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xC2 ┬
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xC3 ├
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xC4 ─
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xFF, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xC5 ┼
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F, 0x10, 0x1F, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xC6 ╞
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x2F, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xC7 ╟
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x2F, 0x28, 0x0F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xC8 ╚
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x00, 0x2F, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xC9 ╔
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xFF, 0x28, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xCA ╩
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x00, 0xFF, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xCB ╦
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x2F, 0x28, 0x2F, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xCC ╠
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x00, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xCD ═
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xFF, 0x28, 0xFF, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xCE ╬
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xFF, 0x10, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xCF ╧
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xD0 ╨
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x00, 0xFF, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xD1 ╤
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xD2 ╥
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x2F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xD3 ╙
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x1F, 0x10, 0x0F, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xD4 ╘
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x00, 0x1F, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xD5 ╒
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xD6 ╓
+	{0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0xFF, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28, 0x28}, // 0xD7 ╫
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xFF, 0x10, 0xFF, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xD8 ╪
+	{0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0xF0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xD9 ┘
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x0F, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10, 0x10}, // 0xDA ┌
+	{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, // 0xDB █
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}, // 0xDC ▄
+	{0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0, 0xF0}, // 0xDD ▌
+	{0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F, 0x0F}, // 0xDE ▐
+	{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xDF ▀
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x76, 0xDC, 0xCC, 0xCC, 0xCC, 0xDC, 0x76, 0x00, 0x00, 0x00, 0x00}, // 0xE0 α
+	{0x00, 0x78, 0xC4, 0xCC, 0xDC, 0xD8, 0xD8, 0xD8, 0xCC, 0xCC, 0xCC, 0xD8, 0x00, 0x00, 0x00, 0x00}, // 0xE1 ß
+	{0x00, 0x00, 0xFE, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0xC0, 0x00, 0x00, 0x00, 0x00}, // 0xE2 Γ
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xFE, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCE, 0x00, 0x00, 0x00, 0x00}, // 0xE3 π
+	{0x00, 0x00, 0xFC, 0xFC, 0xC0, 0x60, 0x30, 0x30, 0x60, 0xE0, 0xFC, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0xE4 Σ
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x7E, 0xDC, 0xCC, 0xCC, 0xCC, 0xFC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0xE5 σ
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xFE, 0xC0, 0xC0, 0xC0, 0x00}, // 0xE6 µ
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xFC, 0x30, 0x30, 0x30, 0x30, 0x38, 0x18, 0x00, 0x00, 0x00, 0x00}, // 0xE7 τ
+	{0x00, 0x00, 0xFC, 0x30, 0x78, 0xCC, 0xCC, 0xCC, 0x78, 0x30, 0xFC, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xE8 Φ
+	{0x00, 0x00, 0x38, 0x6C, 0xC6, 0xC6, 0xFE, 0xC6, 0xC6, 0x6C, 0x38, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xE9 Θ
+	{0x00, 0x00, 0x00, 0x38, 0x7C, 0xEE, 0xC6, 0xC6, 0xC6, 0xC6, 0x6C, 0xEE, 0x00, 0x00, 0x00, 0x00}, // 0xEA Ω
+	{0x00, 0x00, 0x78, 0xC0, 0xE0, 0x78, 0xCC, 0xCC, 0xCC, 0xCC, 0xFC, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0xEB δ
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x6C, 0x92, 0x92, 0x6C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xEC ∞
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0xDC, 0xFC, 0xB6, 0xB6, 0xB4, 0xFC, 0x78, 0x30, 0x30, 0x30, 0x00}, // 0xED φ
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x78, 0xC4, 0xC0, 0x78, 0xC0, 0xC4, 0x78, 0x00, 0x00, 0x00, 0x00}, // 0xEE ε
+	{0x00, 0x00, 0x00, 0x00, 0x30, 0x78, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0xCC, 0x00, 0x00, 0x00, 0x00}, // 0xEF ∩
+	{0x00, 0x00, 0x00, 0x00, 0xFC, 0xFC, 0x00, 0xFC, 0xFC, 0x00, 0xFC, 0xFC, 0x00, 0x00, 0x00, 0x00}, // 0xF0 ≡
+	{0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0xFE, 0xFE, 0x30, 0x30, 0xFE, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0xF1 ±
+	{0x00, 0x00, 0x00, 0x00, 0xC0, 0xF8, 0x1E, 0x1E, 0xF8, 0xC0, 0xFE, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0xF2 ≥
+	{0x00, 0x00, 0x00, 0x00, 0x06, 0x3E, 0xF0, 0xF0, 0x3E, 0x06, 0xFE, 0xFE, 0x00, 0x00, 0x00, 0x00}, // 0xF3 ≤
+	{0x0E, 0x1A, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18}, // 0xF4 ⌠
+	{0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x18, 0x58, 0x70}, // 0xF5 ⌡
+	{0x00, 0x00, 0x00, 0x00, 0x30, 0x30, 0x00, 0xFE, 0xFE, 0x00, 0x30, 0x30, 0x00, 0x00, 0x00, 0x00}, // 0xF6 ÷
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x72, 0x8C, 0x72, 0x8C, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xF7 ≈
+	{0x00, 0x00, 0x30, 0x48, 0x48, 0x30, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xF8 °
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x18, 0x18, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xF9 ∙
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xFA ·
+	{0x00, 0x04, 0x0C, 0x08, 0x08, 0x18, 0xD0, 0xD0, 0x70, 0x70, 0x60, 0x20, 0x00, 0x00, 0x00, 0x00}, // 0xFB √
+	{0x00, 0x00, 0x00, 0x00, 0x78, 0x48, 0x48, 0x48, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xFC ⁿ
+	{0x00, 0x00, 0x78, 0x08, 0x10, 0x20, 0x78, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xFD ²
+	{0x00, 0x00, 0x00, 0x00, 0x7C, 0x7C, 0x7C, 0x7C, 0x7C, 0x7C, 0x7C, 0x7C, 0x00, 0x00, 0x00, 0x00}, // 0xFE ■
+	{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, // 0xFF
+}
@@ -0,0 +1,139 @@
+package wadloader
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strconv"
+)
+
+// COLORMAP holds 32 light levels (brightest first), the invulnerability
+// map and an unused all black table, each one remaps the 256 palette indexes
+const (
+	ColormapLightLevels     = 32
+	ColormapInvulnerability = 32
+	colormapStripCellSize   = 4
+)
+
+type Colormap [256]uint8
+
+func (wl *WADLoader) DetectColormaps() ([]Colormap, error) {
+	var colormaps []Colormap
+
+	if len(wl.WADLumps) < 1 {
+		return colormaps, errors.New("[Warn] DetectColormaps: No Lumps loaded, cannot detect colormaps")
+	}
+
+	for _, lump := range wl.WADLumps {
+		if string(bytes.Trim(lump.LumpName[:], "\x00")) != "COLORMAP" {
+			continue
+		}
+
+		lumpData, err := wl.ReadLumpData(lump)
+		if err != nil {
+			return colormaps, errors.New("[Error] DetectColormaps: Cannot read the COLORMAP lump - " + err.Error())
+		}
+
+		for i := 0; i+256 <= len(lumpData); i += 256 {
+			var colormap Colormap
+			copy(colormap[:], lumpData[i:i+256])
+			colormaps = append(colormaps, colormap)
+		}
+		break
+	}
+
+	if len(colormaps) < 1 {
+		return colormaps, errors.New("[Warn] DetectColormaps: Couldn't detect colormaps")
+	}
+
+	return colormaps, nil
+}
+
+func (wl *WADLoader) LoadColormaps() error {
+	colormaps, err := wl.DetectColormaps()
+	if err != nil {
+		return err
+	}
+
+	wl.Colormaps = colormaps
+
+	return nil
+}
+
+// sector light levels go from 0 (dark) to 255, every 8 levels use the next darker colormap
+func (wl *WADLoader) SetLightLevel(lightLevel int) error {
+	if lightLevel < 0 || lightLevel > 255 {
+		return errors.New("[Error] SetLightLevel: The light level must be between 0 and 255")
+	}
+
+	return wl.SetRenderColormap((255 - lightLevel) / 8)
+}
+
+func (wl *WADLoader) SetRenderColormap(colormapIdx int) error {
+	if colormapIdx < 0 || colormapIdx >= len(wl.Colormaps) {
+		return errors.New("[Error] SetRenderColormap: No colormap " + strconv.Itoa(colormapIdx) + " loaded")
+	}
+
+	wl.RenderColormap = &wl.Colormaps[colormapIdx]
+
+	return nil
+}
+
+// palette used when exporting graphics, Palettes[0] seen through RenderColormap if any
+func (wl *WADLoader) renderPalette() Palette {
+	if wl.RenderColormap == nil {
+		return wl.Palettes[0]
+	}
+	return ApplyColormap(wl.Palettes[0], *wl.RenderColormap)
+}
+
+func ApplyColormap(palette Palette, colormap Colormap) Palette {
+	var remapped Palette
+	for idx, colorIdx := range colormap {
+		remapped[idx] = palette[colorIdx]
+	}
+	return remapped
+}
+
+// one row per colormap and one column per palette index
+func ColormapsToImage(colormaps []Colormap, palette Palette) *image.RGBA {
+	stripImg := image.NewRGBA(image.Rect(0, 0, 256*colormapStripCellSize, len(colormaps)*colormapStripCellSize))
+
+	for row, colormap := range colormaps {
+		for col, colorIdx := range colormap {
+			pixelColor := palette[colorIdx]
+			cellColor := color.RGBA{pixelColor.Red, pixelColor.Green, pixelColor.Blue, 255}
+
+			for y := 0; y < colormapStripCellSize; y++ {
+				for x := 0; x < colormapStripCellSize; x++ {
+					stripImg.Set(col*colormapStripCellSize+x, row*colormapStripCellSize+y, cellColor)
+				}
+			}
+		}
+	}
+
+	return stripImg
+}
+
+func (wl *WADLoader) ExportColormaps(filename string) error {
+	if len(wl.Colormaps) < 1 || len(wl.Palettes) < 1 {
+		return errors.New("[Error] ExportColormaps: Colormaps and palettes must be loaded first")
+	}
+
+	stripFile, err := os.Create(filename)
+	if err != nil {
+		return errors.New("[Error] ExportColormaps: Cannot create the target file - " + err.Error())
+	}
+
+	defer stripFile.Close()
+
+	err = png.Encode(stripFile, ColormapsToImage(wl.Colormaps, wl.Palettes[0]))
+	if err != nil {
+		return errors.New("[Error] ExportColormaps: Cannot encode the PNG - " + err.Error())
+	}
+
+	return nil
+}
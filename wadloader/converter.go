@@ -0,0 +1,275 @@
+package wadloader
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// namespaces become folders, maps are stored as maps/<NAME>.wad and
+// sprites, patches and flats are optionally converted to PNG
+func (wl *WADLoader) ConvertToPK3(outputFilename string, convertGraphics bool) error {
+	if outputFilename == "" {
+		return errors.New("[Error] ConvertToPK3: No filename specified")
+	}
+
+	if convertGraphics && len(wl.Palettes) < 1 {
+		return errors.New("[Error] ConvertToPK3: Palettes must be loaded to convert graphics to PNG")
+	}
+
+	wadFS, err := wl.FS()
+	if err != nil {
+		return errors.New("[Error] ConvertToPK3: Cannot read the WAD directory - " + err.Error())
+	}
+
+	pk3File, err := os.Create(outputFilename)
+	if err != nil {
+		return errors.New("[Error] ConvertToPK3: Cannot create the target file - " + err.Error())
+	}
+
+	defer pk3File.Close()
+
+	zipWriter := zip.NewWriter(pk3File)
+
+	err = wl.writePK3Folder(zipWriter, wadFS.root, "", convertGraphics)
+	if err != nil {
+		zipWriter.Close()
+		return err
+	}
+
+	err = zipWriter.Close()
+	if err != nil {
+		return errors.New("[Error] ConvertToPK3: Cannot finish the PK3 file - " + err.Error())
+	}
+
+	return nil
+}
+
+func (wl *WADLoader) writePK3Folder(zipWriter *zip.Writer, dir *wadFSNode, folder string, convertGraphics bool) error {
+	// later lumps override earlier ones in a WAD, a PK3 folder can only hold one of them
+	lastLumpIdx := make(map[string]int)
+	for childIdx, child := range dir.children {
+		if !child.isDir {
+			lastLumpIdx[string(bytes.Trim(child.lump.LumpName[:], "\x00"))] = childIdx
+		}
+	}
+
+	for childIdx, child := range dir.children {
+		if child.isDir && folder == "" && child.name == "maps" {
+			err := wl.writePK3Maps(zipWriter, child)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		if child.isDir {
+			err := wl.writePK3Folder(zipWriter, child, folder+child.name+"/", convertGraphics)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		lumpName := string(bytes.Trim(child.lump.LumpName[:], "\x00"))
+		if lastLumpIdx[lumpName] != childIdx {
+			fmt.Println("[Warn] ConvertToPK3: Duplicated lump " + lumpName + " in /" + folder + ", only the last one is kept")
+			continue
+		}
+
+		fileFolder, fileData, fileExt, err := wl.pk3FileForLump(child.lump, folder, convertGraphics)
+		if err != nil {
+			return err
+		}
+
+		filePath := fileFolder + strings.ReplaceAll(lumpName, "\\", "^") + "." + fileExt
+
+		err = writeZipFile(zipWriter, filePath, fileData)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// returns the folder, data and extension the lump uses inside the PK3
+func (wl *WADLoader) pk3FileForLump(lump Lump, folder string, convertGraphics bool) (string, []byte, string, error) {
+	lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+	lumpData, err := wl.ReadLumpData(lump)
+	if err != nil {
+		return folder, nil, "", err
+	}
+
+	topFolder := strings.SplitN(folder, "/", 2)[0]
+
+	isMusic := topFolder == "music" || (folder == "" && strings.HasPrefix(lumpName, "D_"))
+	if isMusic {
+		musicFormat, _ := wl.WADParser.getMusicFormatFromLump(&lump)
+		if folder == "" {
+			folder = "music/"
+		}
+		return folder, lumpData, musicFormatExtension(musicFormat), nil
+	}
+
+	if folder == "" && (strings.HasPrefix(lumpName, "DS") || strings.HasPrefix(lumpName, "DP")) {
+		return "sounds/", lumpData, "lmp", nil
+	}
+
+	if !convertGraphics || bytes.HasPrefix(lumpData, []byte("\x89PNG")) {
+		return folder, lumpData, "lmp", nil
+	}
+
+	switch topFolder {
+	case "sprites", "patches":
+		patch, err := parsePatchData(lumpName, lumpData)
+		if err != nil {
+			fmt.Println("[Warn] ConvertToPK3: Cannot convert " + lumpName + " to PNG, keeping the raw lump")
+			return folder, lumpData, "lmp", nil
+		}
+
+		pngData, err := encodePNGWithOffsets(PatchToImage(patch, wl.Palettes[0]), patch.LeftOffset, patch.TopOffset)
+		if err != nil {
+			return folder, nil, "", err
+		}
+
+		return folder, pngData, "png", nil
+	case "flats":
+		if len(lumpData) != 4096 {
+			return folder, lumpData, "lmp", nil
+		}
+
+		var flat Flat
+		flat.Name = lumpName
+		copy(flat.PixelData[:], lumpData)
+
+		pngData, err := encodePNGWithOffsets(FlatToImage(flat, wl.Palettes[0]), 0, 0)
+		if err != nil {
+			return folder, nil, "", err
+		}
+
+		return folder, pngData, "png", nil
+	}
+
+	return folder, lumpData, "lmp", nil
+}
+
+func (wl *WADLoader) writePK3Maps(zipWriter *zip.Writer, mapsDir *wadFSNode) error {
+	for _, mapDir := range mapsDir.children {
+		mapWAD := WADWriter{WadType: "PWAD"}
+
+		// the map header comes first, with its data if it had any
+		mapName := mapDir.name
+		if len(mapDir.children) > 0 && mapDir.children[0].name == mapName {
+			headerData, err := wl.ReadLumpData(mapDir.children[0].lump)
+			if err != nil {
+				return err
+			}
+			mapWAD.AddLump(mapName, headerData)
+		} else {
+			mapWAD.AddMarker(mapName)
+		}
+
+		for _, mapLump := range mapDir.children {
+			if mapLump.name == mapName {
+				continue
+			}
+
+			lumpData, err := wl.ReadLumpData(mapLump.lump)
+			if err != nil {
+				return err
+			}
+
+			mapWAD.AddLump(string(bytes.Trim(mapLump.lump.LumpName[:], "\x00")), lumpData)
+		}
+
+		err := writeZipFile(zipWriter, "maps/"+mapName+".wad", mapWAD.Bytes())
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func writeZipFile(zipWriter *zip.Writer, filePath string, data []byte) error {
+	fileWriter, err := zipWriter.Create(filePath)
+	if err != nil {
+		return errors.New("[Error] writeZipFile: Cannot add " + filePath + " to the archive - " + err.Error())
+	}
+
+	_, err = fileWriter.Write(data)
+	if err != nil {
+		return errors.New("[Error] writeZipFile: Cannot write " + filePath + " - " + err.Error())
+	}
+
+	return nil
+}
+
+// adds a grAb chunk after IHDR so ports like GZDoom keep the graphic offsets
+func encodePNGWithOffsets(img image.Image, leftOffset int16, topOffset int16) ([]byte, error) {
+	var pngData bytes.Buffer
+
+	err := png.Encode(&pngData, img)
+	if err != nil {
+		return nil, errors.New("[Error] encodePNGWithOffsets: Cannot encode the PNG - " + err.Error())
+	}
+
+	if leftOffset == 0 && topOffset == 0 {
+		return pngData.Bytes(), nil
+	}
+
+	var grabChunk bytes.Buffer
+	binary.Write(&grabChunk, binary.BigEndian, uint32(8))
+	grabChunk.WriteString("grAb")
+	binary.Write(&grabChunk, binary.BigEndian, int32(leftOffset))
+	binary.Write(&grabChunk, binary.BigEndian, int32(topOffset))
+	binary.Write(&grabChunk, binary.BigEndian, crc32.ChecksumIEEE(grabChunk.Bytes()[4:]))
+
+	// 8 bytes signature + IHDR chunk (4 length + 4 type + 13 data + 4 crc)
+	ihdrEnd := 8 + 25
+	encoded := pngData.Bytes()
+
+	var result bytes.Buffer
+	result.Write(encoded[:ihdrEnd])
+	result.Write(grabChunk.Bytes())
+	result.Write(encoded[ihdrEnd:])
+
+	return result.Bytes(), nil
+}
+
+// PK3 namespaces are already wrapped with their markers when loaded, so every lump is written as is
+func (wl *WADLoader) ConvertToWAD(outputFilename string) error {
+	if len(wl.WADLumps) < 1 {
+		return errors.New("[Error] ConvertToWAD: No lumps loaded")
+	}
+
+	wadWriter := WADWriter{WadType: "PWAD"}
+
+	for _, lump := range wl.WADLumps {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+		if lump.LumpSize == 0 {
+			wadWriter.AddMarker(lumpName)
+			continue
+		}
+
+		lumpData, err := wl.ReadLumpData(lump)
+		if err != nil {
+			fmt.Println("[Warn] ConvertToWAD: Skipping unreadable lump " + lumpName)
+			continue
+		}
+
+		wadWriter.AddLump(lumpName, lumpData)
+	}
+
+	return wadWriter.WriteFile(outputFilename)
+}
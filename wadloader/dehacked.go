@@ -0,0 +1,440 @@
+package wadloader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DehackedThing   = "Thing"
+	DehackedFrame   = "Frame"
+	DehackedWeapon  = "Weapon"
+	DehackedAmmo    = "Ammo"
+	DehackedSound   = "Sound"
+	DehackedSprite  = "Sprite"
+	DehackedPointer = "Pointer"
+	DehackedCheat   = "Cheat"
+	DehackedMisc    = "Misc"
+)
+
+var dehackedBlockKinds = []string{DehackedThing, DehackedFrame, DehackedWeapon, DehackedAmmo, DehackedSound, DehackedSprite, DehackedPointer, DehackedCheat, DehackedMisc}
+
+var (
+	dehackedBlockRegex = regexp.MustCompile(`(?i)^(Thing|Frame|Weapon|Ammo|Sound|Sprite|Pointer|Cheat|Misc)\s+(\d+)\s*(?:\((.*)\))?`)
+	dehackedTextRegex  = regexp.MustCompile(`(?i)^Text\s+(\d+)\s+(\d+)\s*$`)
+	dehackedFrameRegex = regexp.MustCompile(`(?i)^Frame\s+(\d+)$`)
+	dehackedParRegex   = regexp.MustCompile(`(?i)^par\s+(\d+)\s+(\d+)(?:\s+(\d+))?`)
+)
+
+type DehackedField struct {
+	Name  string
+	Value string
+}
+
+// a "Thing 12 (Imp)" style block and its "name = value" lines
+type DehackedBlock struct {
+	Kind   string
+	Number int
+	// the text between parentheses, Pointer blocks name their frame there
+	Label  string
+	Fields []DehackedField
+}
+
+// Text blocks replace a string of the executable with another one
+type DehackedText struct {
+	Old string
+	New string
+}
+
+// BEX [PARS] entries, episode 0 is a Doom 2 map
+type DehackedPar struct {
+	Episode int
+	Map     int
+	Seconds int
+}
+
+type DehackedPatch struct {
+	DoomVersion int
+	PatchFormat int
+	Blocks      []DehackedBlock
+	Texts       []DehackedText
+	// BEX [STRINGS] mnemonics and their new text
+	Strings map[string]string
+	// BEX [CODEPTR] frame numbers and their code pointer
+	CodePointers map[int]string
+	Pars         []DehackedPar
+	// lines that couldn't be understood, the rest of the patch is still read
+	Problems []string
+}
+
+func (db *DehackedBlock) Field(name string) (string, bool) {
+	for _, field := range db.Fields {
+		if strings.EqualFold(field.Name, name) {
+			return field.Value, true
+		}
+	}
+	return "", false
+}
+
+func (db *DehackedBlock) IntField(name string) (int, bool) {
+	value, found := db.Field(name)
+	if !found {
+		return 0, false
+	}
+
+	number, err := strconv.Atoi(value)
+	return number, err == nil
+}
+
+// DeHackEd v3 patches with the BEX extensions of Boom
+func ParseDehacked(data []byte) (DehackedPatch, error) {
+	patch := DehackedPatch{
+		Strings:      make(map[string]string),
+		CodePointers: make(map[int]string),
+	}
+
+	// Text lengths count line breaks as one character
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	if !strings.Contains(text, "=") && !strings.Contains(strings.ToLower(text), "patch file for dehacked") {
+		return patch, errors.New("[Error] ParseDehacked: The data doesn't look like a DeHackEd patch")
+	}
+
+	var block *DehackedBlock
+	section := ""
+	stringName := ""
+	pos, lineNumber := 0, 0
+
+	readLine := func() string {
+		end := strings.IndexByte(text[pos:], '\n')
+		line := ""
+		if end < 0 {
+			line, pos = text[pos:], len(text)
+		} else {
+			line, pos = text[pos:pos+end], pos+end+1
+		}
+		lineNumber++
+		return line
+	}
+
+	addProblem := func(message string) {
+		patch.Problems = append(patch.Problems, fmt.Sprintf("line %d: %s", lineNumber, message))
+	}
+
+	closeBlock := func() {
+		if block != nil {
+			patch.Blocks = append(patch.Blocks, *block)
+			block = nil
+		}
+	}
+
+	for pos < len(text) {
+		rawLine := readLine()
+		line := strings.TrimSpace(rawLine)
+
+		// [STRINGS] values go on over the lines ending with a backslash
+		if stringName != "" {
+			continued := strings.HasSuffix(line, "\\")
+			patch.Strings[stringName] += unescapeDehackedString(strings.TrimSuffix(line, "\\"))
+			if !continued {
+				stringName = ""
+			}
+			continue
+		}
+
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(strings.ToLower(line), "patch file for dehacked") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			closeBlock()
+			section = strings.ToUpper(strings.Trim(line, "[]"))
+			if section != "STRINGS" && section != "CODEPTR" && section != "PARS" {
+				addProblem("BEX section [" + section + "] is not read")
+			}
+			continue
+		}
+
+		if match := dehackedTextRegex.FindStringSubmatch(line); match != nil {
+			closeBlock()
+			section = ""
+			oldLength, errOld := strconv.Atoi(match[1])
+			newLength, errNew := strconv.Atoi(match[2])
+			if errOld != nil || errNew != nil || oldLength < 0 || newLength < 0 {
+				addProblem("cannot read the Text lengths " + line)
+				break
+			}
+
+			// compared one at a time, huge lengths would overflow when added
+			if oldLength > len(text)-pos || newLength > len(text)-pos-oldLength {
+				addProblem("Text block longer than the patch")
+				break
+			}
+
+			patch.Texts = append(patch.Texts, DehackedText{
+				Old: text[pos : pos+oldLength],
+				New: text[pos+oldLength : pos+oldLength+newLength],
+			})
+			lineNumber += strings.Count(text[pos:pos+oldLength+newLength], "\n")
+			pos += oldLength + newLength
+			continue
+		}
+
+		if match := dehackedBlockRegex.FindStringSubmatch(line); match != nil && !strings.Contains(line, "=") {
+			closeBlock()
+			section = ""
+			number, _ := strconv.Atoi(match[2])
+			block = &DehackedBlock{Number: number, Label: strings.TrimSpace(match[3])}
+			for _, kind := range dehackedBlockKinds {
+				if strings.EqualFold(kind, match[1]) {
+					block.Kind = kind
+				}
+			}
+			continue
+		}
+
+		if strings.HasPrefix(strings.ToLower(line), "include ") {
+			addProblem("included patch " + strings.TrimSpace(line[8:]) + " is not followed")
+			continue
+		}
+
+		// the lines of the sections that aren't read were already reported with their header
+		if section != "" && section != "STRINGS" && section != "CODEPTR" && section != "PARS" {
+			continue
+		}
+
+		if section == "PARS" {
+			match := dehackedParRegex.FindStringSubmatch(line)
+			if match == nil {
+				addProblem("cannot read the par time " + line)
+				continue
+			}
+
+			numbers := []int{}
+			for _, value := range match[1:] {
+				if value != "" {
+					number, _ := strconv.Atoi(value)
+					numbers = append(numbers, number)
+				}
+			}
+
+			if len(numbers) == 3 {
+				patch.Pars = append(patch.Pars, DehackedPar{Episode: numbers[0], Map: numbers[1], Seconds: numbers[2]})
+			} else {
+				patch.Pars = append(patch.Pars, DehackedPar{Map: numbers[0], Seconds: numbers[1]})
+			}
+			continue
+		}
+
+		separator := strings.IndexByte(line, '=')
+		if separator < 0 {
+			addProblem("cannot read " + line)
+			continue
+		}
+
+		name := strings.TrimSpace(line[:separator])
+		value := strings.TrimSpace(line[separator+1:])
+
+		switch {
+		case section == "STRINGS":
+			// the raw value keeps the spaces before a continuation backslash
+			rawValue := strings.TrimSpace(rawLine[strings.IndexByte(rawLine, '=')+1:])
+			stringName = strings.ToUpper(name)
+			patch.Strings[stringName] = unescapeDehackedString(strings.TrimSuffix(rawValue, "\\"))
+			if !strings.HasSuffix(rawValue, "\\") {
+				stringName = ""
+			}
+		case section == "CODEPTR":
+			match := dehackedFrameRegex.FindStringSubmatch(name)
+			if match == nil {
+				addProblem("cannot read the code pointer " + line)
+				continue
+			}
+			frame, _ := strconv.Atoi(match[1])
+			patch.CodePointers[frame] = value
+		case block != nil:
+			block.Fields = append(block.Fields, DehackedField{Name: name, Value: value})
+		case strings.EqualFold(name, "Doom version"):
+			patch.DoomVersion, _ = strconv.Atoi(value)
+		case strings.EqualFold(name, "Patch format"):
+			patch.PatchFormat, _ = strconv.Atoi(value)
+		default:
+			addProblem(name + " set outside of a block")
+		}
+	}
+
+	closeBlock()
+
+	return patch, nil
+}
+
+// BEX strings use \n for line breaks
+func unescapeDehackedString(value string) string {
+	return strings.ReplaceAll(value, "\\n", "\n")
+}
+
+func (wl *WADLoader) LoadDehacked() (DehackedPatch, error) {
+	lump, found := wl.findLastLump("DEHACKED")
+	if !found {
+		return DehackedPatch{}, errors.New("[Warn] LoadDehacked: No DEHACKED lump found")
+	}
+
+	lumpData, err := wl.ReadLumpData(lump)
+	if err != nil {
+		return DehackedPatch{}, err
+	}
+
+	return ParseDehacked(lumpData)
+}
+
+// .deh and .bex files
+func LoadDehackedFile(filename string) (DehackedPatch, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return DehackedPatch{}, errors.New("[Error] LoadDehackedFile: Cannot read " + filename + " - " + err.Error())
+	}
+
+	return ParseDehacked(data)
+}
+
+// one line per value that differs from the vanilla Doom 1.9 one, values without a
+// known default are listed as they are
+func (dp *DehackedPatch) Report() []string {
+	var lines []string
+
+	for _, block := range dp.Blocks {
+		title := fmt.Sprintf("%s %d", block.Kind, block.Number)
+		if name := dehackedBlockName(block); name != "" {
+			title += " (" + name + ")"
+		}
+
+		for _, field := range block.Fields {
+			defaultValue, found := dehackedDefault(block.Kind, block.Number, field.Name)
+			if !found {
+				lines = append(lines, fmt.Sprintf("%s: %s = %s", title, field.Name, field.Value))
+				continue
+			}
+
+			if strconv.Itoa(defaultValue) == field.Value {
+				continue
+			}
+
+			lines = append(lines, fmt.Sprintf("%s: %s %d -> %s", title, field.Name, defaultValue, field.Value))
+		}
+	}
+
+	for _, text := range dp.Texts {
+		lines = append(lines, fmt.Sprintf("Text: %q -> %q", text.Old, text.New))
+	}
+
+	for _, name := range sortedStrings(dp.Strings) {
+		lines = append(lines, fmt.Sprintf("String %s: %q", name, dp.Strings[name]))
+	}
+
+	frames := make(map[int]bool)
+	for frame := range dp.CodePointers {
+		frames[frame] = true
+	}
+	for _, frame := range sortedKeys(frames) {
+		lines = append(lines, fmt.Sprintf("Frame %d code pointer: %s", frame, dp.CodePointers[frame]))
+	}
+
+	for _, par := range dp.Pars {
+		mapName := fmt.Sprintf("MAP%02d", par.Map)
+		if par.Episode > 0 {
+			mapName = fmt.Sprintf("E%dM%d", par.Episode, par.Map)
+		}
+
+		if defaultPar, found := dehackedDefaultPar(par.Episode, par.Map); found {
+			if defaultPar != par.Seconds {
+				lines = append(lines, fmt.Sprintf("Par %s: %d -> %d seconds", mapName, defaultPar, par.Seconds))
+			}
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("Par %s: %d seconds", mapName, par.Seconds))
+	}
+
+	return lines
+}
+
+func sortedStrings(values map[string]string) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// the vanilla name when it's known, otherwise the one given by the patch
+func dehackedBlockName(block DehackedBlock) string {
+	switch block.Kind {
+	case DehackedThing:
+		if defaults, found := vanillaThings[block.Number]; found {
+			return defaults.Name
+		}
+	case DehackedWeapon:
+		if block.Number >= 0 && block.Number < len(vanillaWeapons) {
+			return vanillaWeapons[block.Number].Name
+		}
+	case DehackedAmmo:
+		if block.Number >= 0 && block.Number < len(vanillaAmmo) {
+			return vanillaAmmo[block.Number].Name
+		}
+	}
+	return block.Label
+}
+
+// copy of the profile with the editor numbers and hit points of the patched things,
+// only the Doom based profiles are patched
+func (gp *GameProfile) WithDehacked(patch DehackedPatch) GameProfile {
+	profile := *gp
+	if !gp.DehackedThings {
+		return profile
+	}
+
+	profile.ThingTypes = make(map[uint16]ThingInfo, len(gp.ThingTypes))
+	for thingType, info := range gp.ThingTypes {
+		profile.ThingTypes[thingType] = info
+	}
+
+	// things are moved after every old number is removed, so patches swapping numbers work
+	moved := make(map[uint16]ThingInfo)
+
+	for _, block := range patch.Blocks {
+		defaults, known := vanillaThings[block.Number]
+		if block.Kind != DehackedThing || !known || defaults.ID < 0 {
+			continue
+		}
+
+		info, found := gp.ThingTypes[uint16(defaults.ID)]
+		if !found {
+			continue
+		}
+
+		if hitPoints, found := block.IntField("Hit points"); found && info.Category == ThingCategoryMonster {
+			info.Health = hitPoints
+		}
+
+		newID := defaults.ID
+		if id, found := block.IntField("ID #"); found {
+			newID = id
+		}
+
+		delete(profile.ThingTypes, uint16(defaults.ID))
+		if newID > 0 && newID <= 0xFFFF {
+			moved[uint16(newID)] = info
+		}
+	}
+
+	for thingType, info := range moved {
+		profile.ThingTypes[thingType] = info
+	}
+
+	return profile
+}
@@ -0,0 +1,242 @@
+package wadloader
+
+import "strings"
+
+// vanilla Doom 1.9 values changed by DeHackEd patches. Things are listed when they have an
+// editor number, projectiles, effects and decorations aren't, neither are the frames
+
+type dehackedThingDefaults struct {
+	Name         string
+	ID           int
+	HitPoints    int
+	Speed        int
+	Radius       int
+	Height       int
+	Mass         int
+	Damage       int
+	ReactionTime int
+	PainChance   int
+}
+
+// pickups, keys and markers share the same size and values
+func vanillaItem(name string, id int) dehackedThingDefaults {
+	return dehackedThingDefaults{name, id, 1000, 0, 20, 16, 100, 0, 8, 0}
+}
+
+// DeHackEd numbers the things from 1, in the order of the executable table
+var vanillaThings = map[int]dehackedThingDefaults{
+	1:  {"Player", -1, 100, 0, 16, 56, 100, 0, 0, 255},
+	2:  {"Zombieman", 3004, 20, 8, 20, 56, 100, 0, 8, 200},
+	3:  {"Shotgun guy", 9, 30, 8, 20, 56, 100, 0, 8, 170},
+	4:  {"Arch-vile", 64, 700, 15, 20, 56, 500, 0, 8, 10},
+	6:  {"Revenant", 66, 300, 10, 20, 56, 500, 0, 8, 100},
+	9:  {"Mancubus", 67, 600, 8, 48, 64, 1000, 0, 8, 80},
+	11: {"Heavy weapon dude", 65, 70, 8, 20, 56, 100, 0, 8, 170},
+	12: {"Imp", 3001, 60, 8, 20, 56, 100, 0, 8, 200},
+	13: {"Demon", 3002, 150, 10, 30, 56, 400, 0, 8, 180},
+	14: {"Spectre", 58, 150, 10, 30, 56, 400, 0, 8, 180},
+	15: {"Cacodemon", 3005, 400, 8, 31, 56, 400, 0, 8, 128},
+	16: {"Baron of Hell", 3003, 1000, 8, 24, 64, 1000, 0, 8, 50},
+	18: {"Hell knight", 69, 500, 8, 24, 64, 1000, 0, 8, 50},
+	19: {"Lost soul", 3006, 100, 8, 16, 56, 50, 3, 8, 256},
+	20: {"Spider Mastermind", 7, 3000, 12, 128, 100, 1000, 0, 8, 40},
+	21: {"Arachnotron", 68, 500, 12, 64, 64, 600, 0, 8, 128},
+	22: {"Cyberdemon", 16, 4000, 16, 40, 110, 1000, 0, 8, 20},
+	23: {"Pain elemental", 71, 400, 8, 31, 56, 400, 0, 8, 128},
+	24: {"Wolfenstein SS", 84, 50, 8, 20, 56, 100, 0, 8, 170},
+	25: {"Commander Keen", 72, 100, 0, 16, 72, 10000000, 0, 8, 256},
+	26: {"Boss brain", 88, 250, 0, 16, 16, 10000000, 0, 8, 255},
+	27: {"Boss shooter", 89, 1000, 0, 20, 32, 100, 0, 8, 0},
+	28: {"Spawn spot", 87, 1000, 0, 20, 32, 100, 0, 8, 0},
+	31: {"Exploding barrel", 2035, 20, 0, 10, 42, 100, 0, 8, 0},
+	42: vanillaItem("Teleport landing", 14),
+	44: vanillaItem("Armor", 2018),
+	45: vanillaItem("Megaarmor", 2019),
+	46: vanillaItem("Health bonus", 2014),
+	47: vanillaItem("Armor bonus", 2015),
+	48: vanillaItem("Blue keycard", 5),
+	49: vanillaItem("Red keycard", 13),
+	50: vanillaItem("Yellow keycard", 6),
+	51: vanillaItem("Yellow skull key", 39),
+	52: vanillaItem("Red skull key", 38),
+	53: vanillaItem("Blue skull key", 40),
+	54: vanillaItem("Stimpack", 2011),
+	55: vanillaItem("Medikit", 2012),
+	56: vanillaItem("Soulsphere", 2013),
+	57: vanillaItem("Invulnerability", 2022),
+	58: vanillaItem("Berserk", 2023),
+	59: vanillaItem("Partial invisibility", 2024),
+	60: vanillaItem("Radiation shielding suit", 2025),
+	61: vanillaItem("Computer area map", 2026),
+	62: vanillaItem("Light amplification visor", 2045),
+	63: vanillaItem("Megasphere", 83),
+	64: vanillaItem("Clip", 2007),
+	65: vanillaItem("Box of bullets", 2048),
+	66: vanillaItem("Rocket", 2010),
+	67: vanillaItem("Box of rockets", 2046),
+	68: vanillaItem("Energy cell", 2047),
+	69: vanillaItem("Energy cell pack", 17),
+	70: vanillaItem("Shotgun shells", 2008),
+	71: vanillaItem("Box of shells", 2049),
+	72: vanillaItem("Backpack", 8),
+	73: vanillaItem("BFG9000", 2006),
+	74: vanillaItem("Chaingun", 2002),
+	75: vanillaItem("Chainsaw", 2005),
+	76: vanillaItem("Rocket launcher", 2003),
+	77: vanillaItem("Plasma gun", 2004),
+	78: vanillaItem("Shotgun", 2001),
+	79: vanillaItem("Super shotgun", 82),
+}
+
+type dehackedWeaponDefaults struct {
+	Name          string
+	AmmoType      int
+	DeselectFrame int
+	SelectFrame   int
+	BobbingFrame  int
+	ShootingFrame int
+	FiringFrame   int
+}
+
+var vanillaWeapons = []dehackedWeaponDefaults{
+	{"Fist", 5, 3, 4, 2, 5, 0},
+	{"Pistol", 0, 11, 12, 10, 13, 17},
+	{"Shotgun", 1, 19, 20, 18, 21, 30},
+	{"Chaingun", 0, 50, 51, 49, 52, 55},
+	{"Rocket launcher", 3, 58, 59, 57, 60, 63},
+	{"Plasma gun", 2, 75, 76, 74, 77, 79},
+	{"BFG9000", 2, 82, 83, 81, 84, 88},
+	{"Chainsaw", 5, 69, 70, 67, 71, 0},
+	{"Super shotgun", 1, 33, 34, 32, 35, 47},
+}
+
+type dehackedAmmoDefaults struct {
+	Name    string
+	MaxAmmo int
+	PerAmmo int
+}
+
+var vanillaAmmo = []dehackedAmmoDefaults{
+	{"Bullets", 200, 10},
+	{"Shells", 50, 4},
+	{"Cells", 300, 20},
+	{"Rockets", 50, 1},
+}
+
+var vanillaMisc = map[string]int{
+	"initial health":    100,
+	"initial bullets":   50,
+	"max health":        200,
+	"max armor":         200,
+	"green armor class": 1,
+	"blue armor class":  2,
+	"max soulsphere":    200,
+	"soulsphere health": 100,
+	"megasphere health": 200,
+	"god mode health":   100,
+	"idfa armor":        200,
+	"idfa armor class":  2,
+	"idkfa armor":       200,
+	"idkfa armor class": 2,
+	"bfg cells/shot":    40,
+	"monsters infight":  202,
+}
+
+// Doom E1-E3 and Doom 2 par times in seconds
+var (
+	vanillaEpisodePars = [3][9]int{
+		{30, 75, 120, 90, 165, 180, 180, 30, 165},
+		{90, 90, 90, 120, 90, 360, 240, 30, 170},
+		{90, 45, 90, 150, 90, 90, 165, 30, 135},
+	}
+	vanillaMapPars = [32]int{
+		30, 90, 120, 120, 90, 150, 120, 120, 270, 90,
+		210, 150, 150, 150, 210, 150, 420, 150, 210, 150,
+		240, 150, 180, 150, 150, 300, 330, 420, 300, 180,
+		120, 30,
+	}
+)
+
+// DeHackEd stores sizes as 16.16 fixed point
+func dehackedThingDefault(thing dehackedThingDefaults, field string) (int, bool) {
+	switch field {
+	case "id #":
+		return thing.ID, true
+	case "hit points":
+		return thing.HitPoints, true
+	case "speed":
+		return thing.Speed, true
+	case "width":
+		return thing.Radius << 16, true
+	case "height":
+		return thing.Height << 16, true
+	case "mass":
+		return thing.Mass, true
+	case "missile damage":
+		return thing.Damage, true
+	case "reaction time":
+		return thing.ReactionTime, true
+	case "pain chance":
+		return thing.PainChance, true
+	}
+	return 0, false
+}
+
+func dehackedDefault(kind string, number int, field string) (int, bool) {
+	field = strings.ToLower(field)
+
+	switch kind {
+	case DehackedThing:
+		if thing, found := vanillaThings[number]; found {
+			return dehackedThingDefault(thing, field)
+		}
+	case DehackedWeapon:
+		if number < 0 || number >= len(vanillaWeapons) {
+			return 0, false
+		}
+		weapon := vanillaWeapons[number]
+		switch field {
+		case "ammo type":
+			return weapon.AmmoType, true
+		case "deselect frame":
+			return weapon.DeselectFrame, true
+		case "select frame":
+			return weapon.SelectFrame, true
+		case "bobbing frame":
+			return weapon.BobbingFrame, true
+		case "shooting frame":
+			return weapon.ShootingFrame, true
+		case "firing frame":
+			return weapon.FiringFrame, true
+		}
+	case DehackedAmmo:
+		if number < 0 || number >= len(vanillaAmmo) {
+			return 0, false
+		}
+		switch field {
+		case "max ammo":
+			return vanillaAmmo[number].MaxAmmo, true
+		case "per ammo":
+			return vanillaAmmo[number].PerAmmo, true
+		}
+	case DehackedMisc:
+		value, found := vanillaMisc[field]
+		return value, found
+	}
+
+	return 0, false
+}
+
+func dehackedDefaultPar(episode int, mapNumber int) (int, bool) {
+	if episode == 0 {
+		if mapNumber < 1 || mapNumber > len(vanillaMapPars) {
+			return 0, false
+		}
+		return vanillaMapPars[mapNumber-1], true
+	}
+
+	if episode > len(vanillaEpisodePars) || mapNumber < 1 || mapNumber > len(vanillaEpisodePars[0]) {
+		return 0, false
+	}
+	return vanillaEpisodePars[episode-1][mapNumber-1], true
+}
@@ -0,0 +1,52 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	DefaultDMXSoundRate = 11025
+
+	dmxSoundFormat = 3
+	// DMX skips 16 bytes at each side of the samples, they repeat the edge samples
+	dmxSoundPadding = 16
+)
+
+type dmxSoundHeader struct {
+	Format      uint16
+	SampleRate  uint16
+	SampleCount uint32
+}
+
+// DS* lumps: a format 3 header and 8 bits unsigned mono samples
+func EncodeDMXSound(audio WAVAudio, sampleRate int) ([]byte, error) {
+	if sampleRate < 1 || sampleRate > 0xFFFF {
+		return nil, errors.New("[Error] EncodeDMXSound: The sample rate must be between 1 and 65535")
+	}
+
+	mono := audio.Mono()
+	resampled := mono.Resample(sampleRate)
+	if len(resampled.Samples) == 0 {
+		return nil, errors.New("[Error] EncodeDMXSound: The sound has no samples")
+	}
+
+	samples := make([]byte, len(resampled.Samples))
+	for idx, sample := range resampled.Samples {
+		samples[idx] = uint8(int(sample)>>8 + 128)
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, dmxSoundHeader{
+		Format:      dmxSoundFormat,
+		SampleRate:  uint16(sampleRate),
+		SampleCount: uint32(len(samples) + 2*dmxSoundPadding),
+	})
+
+	buf.Write(bytes.Repeat(samples[:1], dmxSoundPadding))
+	buf.Write(samples)
+	buf.Write(bytes.Repeat(samples[len(samples)-1:], dmxSoundPadding))
+
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,236 @@
+package wadloader
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// the exit screens are text mode dumps: 80x25 cells of a code page 437 character
+// and an attribute byte (foreground, background and blink)
+const (
+	TextScreenWidth  = 80
+	TextScreenHeight = 25
+)
+
+// ENDOOM (Doom), ENDTEXT (Heretic and Hexen) and ENDSTRF (Strife)
+var textScreenLumpNames = []string{"ENDOOM", "ENDTEXT", "ENDSTRF"}
+
+var vgaTextColors = [16]color.RGBA{
+	{0x00, 0x00, 0x00, 255}, {0x00, 0x00, 0xAA, 255}, {0x00, 0xAA, 0x00, 255}, {0x00, 0xAA, 0xAA, 255},
+	{0xAA, 0x00, 0x00, 255}, {0xAA, 0x00, 0xAA, 255}, {0xAA, 0x55, 0x00, 255}, {0xAA, 0xAA, 0xAA, 255},
+	{0x55, 0x55, 0x55, 255}, {0x55, 0x55, 0xFF, 255}, {0x55, 0xFF, 0x55, 255}, {0x55, 0xFF, 0xFF, 255},
+	{0xFF, 0x55, 0x55, 255}, {0xFF, 0x55, 0xFF, 255}, {0xFF, 0xFF, 0x55, 255}, {0xFF, 0xFF, 0xFF, 255},
+}
+
+// VGA orders the colors blue, green, red while ANSI goes red, green, blue
+var vgaToANSIColor = [8]int{0, 4, 2, 6, 1, 5, 3, 7}
+
+type TextScreenCell struct {
+	Char      uint8
+	Attribute uint8
+}
+
+type TextScreen struct {
+	Name  string
+	Cells [TextScreenWidth * TextScreenHeight]TextScreenCell
+}
+
+func (tc TextScreenCell) Foreground() int {
+	return int(tc.Attribute & 0x0F)
+}
+
+func (tc TextScreenCell) Background() int {
+	return int(tc.Attribute>>4) & 0x07
+}
+
+func (tc TextScreenCell) Blink() bool {
+	return tc.Attribute&0x80 != 0
+}
+
+func ParseTextScreen(name string, data []byte) (TextScreen, error) {
+	screen := TextScreen{Name: name}
+
+	if len(data) < TextScreenWidth*TextScreenHeight*2 {
+		return screen, errors.New("[Error] ParseTextScreen: " + name + " is too short for an 80x25 text screen")
+	}
+
+	for idx := range screen.Cells {
+		screen.Cells[idx] = TextScreenCell{data[idx*2], data[idx*2+1]}
+	}
+
+	return screen, nil
+}
+
+func (wl *WADLoader) DetectTextScreens() ([]TextScreen, error) {
+	var screens []TextScreen
+
+	for _, lumpName := range textScreenLumpNames {
+		lump, found := wl.findLastLump(lumpName)
+		if !found {
+			continue
+		}
+
+		lumpData, err := wl.ReadLumpData(lump)
+		if err != nil {
+			return screens, err
+		}
+
+		screen, err := ParseTextScreen(lumpName, lumpData)
+		if err != nil {
+			fmt.Println("[Warn] " + err.Error())
+			continue
+		}
+
+		screens = append(screens, screen)
+	}
+
+	if len(screens) < 1 {
+		return screens, errors.New("[Warn] DetectTextScreens: No ENDOOM, ENDTEXT or ENDSTRF lump found")
+	}
+
+	return screens, nil
+}
+
+// colors are set with SGR codes whenever the attribute changes, bright foregrounds use 90-97
+func (ts *TextScreen) ANSI() string {
+	var ansi strings.Builder
+
+	for row := 0; row < TextScreenHeight; row++ {
+		lastAttribute := -1
+
+		for col := 0; col < TextScreenWidth; col++ {
+			cell := ts.Cells[row*TextScreenWidth+col]
+
+			if int(cell.Attribute) != lastAttribute {
+				foreground := 30 + vgaToANSIColor[cell.Foreground()&0x07]
+				if cell.Foreground() > 7 {
+					foreground += 60
+				}
+
+				fmt.Fprintf(&ansi, "\x1b[0;%d;%d", foreground, 40+vgaToANSIColor[cell.Background()])
+				if cell.Blink() {
+					ansi.WriteString(";5")
+				}
+				ansi.WriteString("m")
+
+				lastAttribute = int(cell.Attribute)
+			}
+
+			ansi.WriteRune(cp437Runes[cell.Char])
+		}
+
+		ansi.WriteString("\x1b[0m\n")
+	}
+
+	return ansi.String()
+}
+
+// a standalone page, runs of cells sharing the attribute become one span
+func (ts *TextScreen) HTML() string {
+	var page strings.Builder
+
+	page.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n<title>" + ts.Name + "</title>\n<style>\n")
+	page.WriteString("body { background: #000000; }\n")
+	page.WriteString("pre { font-family: \"Perfect DOS VGA 437\", \"Px437 IBM VGA 8x16\", monospace; font-size: 16px; line-height: 1; }\n")
+	page.WriteString(".blink { animation: blink 0.5s step-start infinite alternate; }\n")
+	page.WriteString("@keyframes blink { 50% { color: transparent; } }\n")
+	page.WriteString("</style>\n</head>\n<body>\n<pre>")
+
+	for row := 0; row < TextScreenHeight; row++ {
+		for col := 0; col < TextScreenWidth; {
+			cell := ts.Cells[row*TextScreenWidth+col]
+
+			var run strings.Builder
+			for ; col < TextScreenWidth && ts.Cells[row*TextScreenWidth+col].Attribute == cell.Attribute; col++ {
+				run.WriteRune(cp437Runes[ts.Cells[row*TextScreenWidth+col].Char])
+			}
+
+			foreground := vgaTextColors[cell.Foreground()]
+			background := vgaTextColors[cell.Background()]
+
+			page.WriteString("<span")
+			if cell.Blink() {
+				page.WriteString(" class=\"blink\"")
+			}
+			fmt.Fprintf(&page, " style=\"color: #%02X%02X%02X; background: #%02X%02X%02X\">%s</span>",
+				foreground.R, foreground.G, foreground.B, background.R, background.G, background.B, html.EscapeString(run.String()))
+		}
+
+		page.WriteString("\n")
+	}
+
+	page.WriteString("</pre>\n</body>\n</html>\n")
+
+	return page.String()
+}
+
+// blinking cells are drawn in their visible state
+func (ts *TextScreen) Image(font *BitmapFont) *image.RGBA {
+	screenImg := image.NewRGBA(image.Rect(0, 0, TextScreenWidth*8, TextScreenHeight*font.Height))
+
+	for idx, cell := range ts.Cells {
+		cellX := (idx % TextScreenWidth) * 8
+		cellY := (idx / TextScreenWidth) * font.Height
+
+		for y, rowBits := range font.Glyphs[cell.Char] {
+			for x := 0; x < 8; x++ {
+				pixelColor := vgaTextColors[cell.Background()]
+				if rowBits&(0x80>>x) != 0 {
+					pixelColor = vgaTextColors[cell.Foreground()]
+				}
+				screenImg.SetRGBA(cellX+x, cellY+y, pixelColor)
+			}
+		}
+	}
+
+	return screenImg
+}
+
+// writes NAME.ans (UTF-8 with ANSI colors), NAME.html and NAME.png
+func (ts *TextScreen) Export(font *BitmapFont, outputFolder string) error {
+	err := os.WriteFile(outputFolder+"/"+ts.Name+".ans", []byte(ts.ANSI()), 0644)
+	if err != nil {
+		return errors.New("[Error] TextScreen.Export: Cannot write the ANSI file - " + ts.Name + " - " + err.Error())
+	}
+
+	err = os.WriteFile(outputFolder+"/"+ts.Name+".html", []byte(ts.HTML()), 0644)
+	if err != nil {
+		return errors.New("[Error] TextScreen.Export: Cannot write the HTML file - " + ts.Name + " - " + err.Error())
+	}
+
+	screenFile, err := os.Create(outputFolder + "/" + ts.Name + ".png")
+	if err != nil {
+		return errors.New("[Error] TextScreen.Export: Cannot create the PNG file - " + ts.Name + " - " + err.Error())
+	}
+
+	defer screenFile.Close()
+
+	return png.Encode(screenFile, ts.Image(font))
+}
+
+func (wl *WADLoader) ExportTextScreens(outputFolder string, font *BitmapFont) error {
+	screens, err := wl.DetectTextScreens()
+	if err != nil {
+		return err
+	}
+
+	outputFolder, err = createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	for _, screen := range screens {
+		err := screen.Export(font, outputFolder)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,303 @@
+package wadloader
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	fontPageSize     = 256
+	fontSheetColumns = 16
+)
+
+// STCFN033 holds the character 33 ('!'), Heretic's FONTA01/FONTB01 start on that same character
+var fontGlyphRegex = regexp.MustCompile(`^(STCFN)(\d{3})$|^(FONTA|FONTB)(\d{2})$`)
+
+// the game draws spaces as a fixed advance, there is no glyph for them
+var fontSpaceWidths = map[string]int{"STCFN": 4, "FONTA": 5, "FONTB": 8}
+
+type FontGlyph struct {
+	Char  int
+	Patch *Patch
+}
+
+type GameFont struct {
+	Name   string
+	Glyphs []FontGlyph
+}
+
+// glyphs from the loose graphics and the patches, later lumps override earlier ones
+func (wl *WADLoader) DetectFonts() []GameFont {
+	fontsByName := make(map[string]map[int]*Patch)
+
+	for _, patches := range [][]Patch{wl.Patches, wl.Graphics} {
+		for idx := range patches {
+			matches := fontGlyphRegex.FindStringSubmatch(patches[idx].Name)
+			if matches == nil {
+				continue
+			}
+
+			fontName, charCode := matches[1], matches[2]
+			offset := 0
+			if fontName == "" {
+				fontName, charCode = matches[3], matches[4]
+				offset = 32
+			}
+
+			char, _ := strconv.Atoi(charCode)
+
+			if fontsByName[fontName] == nil {
+				fontsByName[fontName] = make(map[int]*Patch)
+			}
+			fontsByName[fontName][char+offset] = &patches[idx]
+		}
+	}
+
+	fontNames := make([]string, 0, len(fontsByName))
+	for fontName := range fontsByName {
+		fontNames = append(fontNames, fontName)
+	}
+	sort.Strings(fontNames)
+
+	fonts := make([]GameFont, 0, len(fontNames))
+	for _, fontName := range fontNames {
+		font := GameFont{Name: fontName}
+		for char, patch := range fontsByName[fontName] {
+			font.Glyphs = append(font.Glyphs, FontGlyph{char, patch})
+		}
+		sort.Slice(font.Glyphs, func(i, j int) bool { return font.Glyphs[i].Char < font.Glyphs[j].Char })
+		fonts = append(fonts, font)
+	}
+
+	return fonts
+}
+
+// glyphs are drawn from the line top moved up by their top offset, every glyph
+// is moved down by the highest one so none of them starts above the line
+func (gf *GameFont) lineMetrics() (int, int) {
+	minTop, lineHeight := 0, 0
+
+	for idx, glyph := range gf.Glyphs {
+		if top := -int(glyph.Patch.TopOffset); idx == 0 || top < minTop {
+			minTop = top
+		}
+	}
+
+	for _, glyph := range gf.Glyphs {
+		if bottom := -int(glyph.Patch.TopOffset) - minTop + int(glyph.Patch.Height); bottom > lineHeight {
+			lineHeight = bottom
+		}
+	}
+
+	return minTop, lineHeight
+}
+
+func (gf *GameFont) spaceWidth() int {
+	if width, found := fontSpaceWidths[gf.Name]; found {
+		return width
+	}
+	return 4
+}
+
+// the game uppercases the text it prints, so missing lowercase letters reuse the uppercase glyphs
+func (gf *GameFont) charAliases() map[int]int {
+	aliases := make(map[int]int)
+	available := make(map[int]bool)
+
+	for _, glyph := range gf.Glyphs {
+		available[glyph.Char] = true
+	}
+
+	for char := 'a'; char <= 'z'; char++ {
+		upper := int(char) - 'a' + 'A'
+		if !available[int(char)] && available[upper] {
+			aliases[int(char)] = upper
+		}
+	}
+
+	return aliases
+}
+
+// AngelCode BMFont text format (.fnt) with the glyphs packed into 256x256 (or smaller) pages
+func (gf *GameFont) ExportBMFont(palette Palette, outputFolder string) error {
+	atlasImages := make([]AtlasImage, 0, len(gf.Glyphs))
+	for _, glyph := range gf.Glyphs {
+		atlasImages = append(atlasImages, AtlasImage{Name: strconv.Itoa(glyph.Char), Image: PatchToImage(*glyph.Patch, palette)})
+	}
+
+	pages, entries, err := PackAtlas(atlasImages, AtlasOptions{MaxPageSize: fontPageSize, Padding: 1})
+	if err != nil {
+		return errors.New("[Error] ExportBMFont: Cannot pack the glyphs of " + gf.Name + " - " + err.Error())
+	}
+
+	entriesByChar := make(map[int]AtlasEntry)
+	for _, entry := range entries {
+		char, _ := strconv.Atoi(entry.Name)
+		entriesByChar[char] = entry
+	}
+
+	minTop, lineHeight := gf.lineMetrics()
+	pageWidth, pageHeight := 0, 0
+
+	var fnt strings.Builder
+	var pageLines strings.Builder
+
+	for idx, page := range pages {
+		pageName := gf.Name + "_" + strconv.Itoa(idx) + ".png"
+
+		pageFile, err := os.Create(outputFolder + "/" + pageName)
+		if err != nil {
+			return errors.New("[Error] ExportBMFont: Cannot create the font page - " + pageName + " - " + err.Error())
+		}
+
+		err = png.Encode(pageFile, page)
+		pageFile.Close()
+		if err != nil {
+			return errors.New("[Error] ExportBMFont: Cannot encode the font page - " + pageName + " - " + err.Error())
+		}
+
+		// every page shares the same size in BMFont, the biggest one is declared
+		if page.Bounds().Dx() > pageWidth {
+			pageWidth = page.Bounds().Dx()
+		}
+		if page.Bounds().Dy() > pageHeight {
+			pageHeight = page.Bounds().Dy()
+		}
+
+		fmt.Fprintf(&pageLines, "page id=%d file=\"%s\"\n", idx, pageName)
+	}
+
+	aliases := gf.charAliases()
+
+	fmt.Fprintf(&fnt, "info face=\"%s\" size=%d bold=0 italic=0 charset=\"\" unicode=0 stretchH=100 smooth=0 aa=1 padding=0,0,0,0 spacing=1,1\n", gf.Name, lineHeight)
+	fmt.Fprintf(&fnt, "common lineHeight=%d base=%d scaleW=%d scaleH=%d pages=%d packed=0\n", lineHeight, lineHeight, pageWidth, pageHeight, len(pages))
+	fnt.WriteString(pageLines.String())
+
+	var charLines strings.Builder
+	charCount := 1
+	fmt.Fprintf(&charLines, "char id=32 x=0 y=0 width=0 height=0 xoffset=0 yoffset=0 xadvance=%d page=0 chnl=15\n", gf.spaceWidth())
+
+	writeChar := func(char int, glyph FontGlyph, entry AtlasEntry) {
+		charCount++
+		fmt.Fprintf(&charLines, "char id=%d x=%d y=%d width=%d height=%d xoffset=%d yoffset=%d xadvance=%d page=%d chnl=15\n",
+			char, entry.X, entry.Y, entry.Width, entry.Height,
+			-int(glyph.Patch.LeftOffset), -int(glyph.Patch.TopOffset)-minTop, glyph.Patch.Width, entry.Page)
+	}
+
+	glyphsByChar := make(map[int]FontGlyph)
+	for _, glyph := range gf.Glyphs {
+		glyphsByChar[glyph.Char] = glyph
+		if entry, found := entriesByChar[glyph.Char]; found {
+			writeChar(glyph.Char, glyph, entry)
+		}
+	}
+
+	aliasChars := make([]int, 0, len(aliases))
+	for char := range aliases {
+		aliasChars = append(aliasChars, char)
+	}
+	sort.Ints(aliasChars)
+
+	for _, char := range aliasChars {
+		if entry, found := entriesByChar[aliases[char]]; found {
+			writeChar(char, glyphsByChar[aliases[char]], entry)
+		}
+	}
+
+	fmt.Fprintf(&fnt, "chars count=%d\n", charCount)
+	fnt.WriteString(charLines.String())
+
+	err = os.WriteFile(outputFolder+"/"+gf.Name+".fnt", []byte(fnt.String()), 0644)
+	if err != nil {
+		return errors.New("[Error] ExportBMFont: Cannot write the font descriptor - " + gf.Name + " - " + err.Error())
+	}
+
+	return nil
+}
+
+// 16 characters per row starting at the space, every cell as wide as the widest glyph and
+// as tall as the line, glyphs aligned on it. Bitmap font editors import this kind of grid
+func (gf *GameFont) SheetImage(palette Palette) *image.RGBA {
+	minTop, lineHeight := gf.lineMetrics()
+	cellWidth, lastChar := 1, 32
+
+	for _, glyph := range gf.Glyphs {
+		if int(glyph.Patch.Width) > cellWidth {
+			cellWidth = int(glyph.Patch.Width)
+		}
+		if glyph.Char > lastChar {
+			lastChar = glyph.Char
+		}
+	}
+
+	rows := (lastChar-32)/fontSheetColumns + 1
+	sheetImg := image.NewRGBA(image.Rect(0, 0, cellWidth*fontSheetColumns, lineHeight*rows))
+
+	for _, glyph := range gf.Glyphs {
+		if glyph.Char < 32 {
+			continue
+		}
+
+		cellX := ((glyph.Char - 32) % fontSheetColumns) * cellWidth
+		cellY := ((glyph.Char-32)/fontSheetColumns)*lineHeight - int(glyph.Patch.TopOffset) - minTop
+
+		for column, post := range glyph.Patch.PatchPosts {
+			for _, postSegment := range post {
+				for i := 0; i < int(postSegment.Length); i++ {
+					pixelColor := palette[postSegment.PixelData[i]]
+					sheetImg.Set(cellX+column, cellY+int(postSegment.TopOffset)+i, color.RGBA{pixelColor.Red, pixelColor.Green, pixelColor.Blue, 255})
+				}
+			}
+		}
+	}
+
+	return sheetImg
+}
+
+func (gf *GameFont) ExportSheet(palette Palette, outputFolder string) error {
+	sheetFile, err := os.Create(outputFolder + "/" + gf.Name + "_sheet.png")
+	if err != nil {
+		return errors.New("[Error] ExportSheet: Cannot create the target file for the font - " + gf.Name + " - " + err.Error())
+	}
+
+	defer sheetFile.Close()
+
+	return png.Encode(sheetFile, gf.SheetImage(palette))
+}
+
+// every font is written as NAME.fnt with its NAME_N.png pages plus a NAME_sheet.png glyph grid
+func (wl *WADLoader) ExportFonts(outputFolder string) error {
+	fonts := wl.DetectFonts()
+	if len(fonts) < 1 {
+		return errors.New("[Warn] ExportFonts: No font glyphs (STCFN, FONTA, FONTB) found")
+	}
+
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	palette := wl.renderPalette()
+
+	for _, font := range fonts {
+		err := font.ExportBMFont(palette, outputFolder)
+		if err != nil {
+			return err
+		}
+
+		err = font.ExportSheet(palette, outputFolder)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,211 @@
+package wadloader
+
+import (
+	"bufio"
+	"bytes"
+	"regexp"
+	"strings"
+)
+
+const (
+	ThingCategoryPlayer   = "player"
+	ThingCategoryMonster  = "monster"
+	ThingCategoryWeapon   = "weapon"
+	ThingCategoryAmmo     = "ammo"
+	ThingCategoryHealth   = "health"
+	ThingCategoryArmor    = "armor"
+	ThingCategoryPowerup  = "powerup"
+	ThingCategoryKey      = "key"
+	ThingCategoryArtifact = "artifact"
+	ThingCategoryOther    = "other"
+)
+
+type ThingInfo struct {
+	Name     string
+	Category string
+	// spawn health, only set for monsters
+	Health int
+}
+
+// game specific assumptions, the loaders fall back to the Doom profile when none is selected
+type GameProfile struct {
+	Name string
+
+	// max palettes read from PLAYPAL, less are read when the lump is shorter
+	PaletteCount int
+
+	// lumps starting with any of the prefixes are music, plus the ones named
+	// by MAPINFO/SNDINFO when MusicFromMapInfo is set
+	MusicPrefixes    []string
+	MusicLumps       []string
+	MusicFromMapInfo bool
+
+	// Hexen format maps use 20 bytes things and 16 bytes linedefs with specials arguments
+	HexenMapFormat bool
+
+	// fullscreen images, raw 320x200 screens when RawFullscreens is set instead of patches
+	FullscreenGraphics []string
+	RawFullscreens     bool
+
+	ThingTypes   map[uint16]ThingInfo
+	LinedefTypes map[uint16]string
+
+	// DeHackEd patches change the thing table, the game runs on the Doom executable
+	DehackedThings bool
+}
+
+var (
+	DoomProfile = GameProfile{
+		Name:               "Doom",
+		PaletteCount:       14,
+		MusicPrefixes:      []string{"D_"},
+		FullscreenGraphics: []string{"TITLEPIC", "CREDIT", "HELP", "HELP1", "HELP2", "VICTORY2", "ENDPIC", "INTERPIC", "BOSSBACK"},
+		ThingTypes:         doomThingTypes,
+		LinedefTypes:       doomLinedefTypes,
+		DehackedThings:     true,
+	}
+
+	HereticProfile = GameProfile{
+		Name:               "Heretic",
+		PaletteCount:       13,
+		MusicPrefixes:      []string{"MUS_"},
+		FullscreenGraphics: []string{"TITLE", "HELP1", "HELP2", "CREDIT", "E2END", "FINAL1", "FINAL2"},
+		RawFullscreens:     true,
+		ThingTypes:         hereticThingTypes,
+		LinedefTypes:       hereticLinedefTypes,
+	}
+
+	HexenProfile = GameProfile{
+		Name:               "Hexen",
+		PaletteCount:       28,
+		MusicLumps:         []string{"HEXEN", "HUB", "HALL", "ORB", "CHESS"},
+		MusicFromMapInfo:   true,
+		HexenMapFormat:     true,
+		FullscreenGraphics: []string{"TITLE", "HELP1", "HELP2", "CREDIT", "FINALE1", "FINALE2", "FINALE3", "INTERPIC"},
+		RawFullscreens:     true,
+		ThingTypes:         hexenThingTypes,
+		LinedefTypes:       hexenLinedefTypes,
+	}
+
+	StrifeProfile = GameProfile{
+		Name:               "Strife",
+		PaletteCount:       14,
+		MusicPrefixes:      []string{"D_"},
+		FullscreenGraphics: []string{"TITLEPIC", "CREDIT", "HELP0", "HELP1", "HELP2", "HELP3"},
+		ThingTypes:         strifeThingTypes,
+		LinedefTypes:       strifeLinedefTypes,
+	}
+
+	ChexProfile = GameProfile{
+		Name:               "Chex Quest",
+		PaletteCount:       14,
+		MusicPrefixes:      []string{"D_"},
+		FullscreenGraphics: []string{"TITLEPIC", "CREDIT", "HELP1", "HELP2", "VICTORY2", "ENDPIC", "INTERPIC"},
+		ThingTypes:         chexThingTypes,
+		LinedefTypes:       doomLinedefTypes,
+		DehackedThings:     true,
+	}
+)
+
+// Final Doom and Freedoom play by the Doom rules
+func ProfileForGame(game string) GameProfile {
+	switch game {
+	case GameHeretic:
+		return HereticProfile
+	case GameHexen:
+		return HexenProfile
+	case GameStrife:
+		return StrifeProfile
+	case GameChex:
+		return ChexProfile
+	}
+
+	return DoomProfile
+}
+
+// identifies the WAD and selects the matching profile
+func (wl *WADLoader) DetectProfile() (WADIdentity, error) {
+	identity, err := wl.Identify()
+	if err != nil {
+		return identity, err
+	}
+
+	profile := ProfileForGame(identity.Game)
+	wl.Profile = &profile
+
+	return identity, nil
+}
+
+func (wl *WADLoader) profile() *GameProfile {
+	if wl.Profile == nil {
+		return &DoomProfile
+	}
+	return wl.Profile
+}
+
+func (gp *GameProfile) ThingInfo(thingType uint16) (ThingInfo, bool) {
+	info, found := gp.ThingTypes[thingType]
+	return info, found
+}
+
+func (gp *GameProfile) LinedefTypeName(lineType uint16) string {
+	if name, found := gp.LinedefTypes[lineType]; found {
+		return name
+	}
+	return "Unknown"
+}
+
+var (
+	mapInfoMusicRegex = regexp.MustCompile(`(?i)^\s*music(?:\s*=\s*|\s+)"?([A-Za-z0-9_\-\\\[\]]{1,8})"?`)
+	sndInfoMusicRegex = regexp.MustCompile(`(?i)^\s*\$MAP\d+\s+"?([A-Za-z0-9_\-\\\[\]]{1,8})"?`)
+)
+
+// Hexen names the song of each map in MAPINFO (ZDoom style), ZMAPINFO (music = "NAME") or SNDINFO ($MAPxx lines)
+func (wl *WADLoader) musicLumpNamesFromMapInfo() map[string]bool {
+	musicNames := make(map[string]bool)
+
+	for _, lump := range wl.WADLumps {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+		var musicRegex *regexp.Regexp
+		switch lumpName {
+		case "MAPINFO", "ZMAPINFO":
+			musicRegex = mapInfoMusicRegex
+		case "SNDINFO":
+			musicRegex = sndInfoMusicRegex
+		default:
+			continue
+		}
+
+		lumpData, err := wl.ReadLumpData(lump)
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(bytes.NewReader(lumpData))
+		for scanner.Scan() {
+			match := musicRegex.FindStringSubmatch(scanner.Text())
+			if match != nil {
+				musicNames[strings.ToUpper(match[1])] = true
+			}
+		}
+	}
+
+	return musicNames
+}
+
+func (gp *GameProfile) isMusicLumpName(lumpName string, mapInfoMusic map[string]bool) bool {
+	for _, prefix := range gp.MusicPrefixes {
+		if strings.HasPrefix(lumpName, prefix) {
+			return true
+		}
+	}
+
+	for _, name := range gp.MusicLumps {
+		if name == lumpName {
+			return true
+		}
+	}
+
+	return mapInfoMusic[lumpName]
+}
@@ -0,0 +1,31 @@
+package wadloader
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestMusicLumpNamesFromMapInfo(t *testing.T) {
+	var ww WADWriter
+	ww.AddLump("MAPINFO", []byte("map MAP01 \"Winnowing Hall\"\n  music WINNOWR\n"))
+	ww.AddLump("ZMAPINFO", []byte("map MAP02 \"Seven Portals\"\n{\n  music = \"D_RUNNIN\"\n  musicorder = 2\n}\n"))
+	ww.AddLump("SNDINFO", []byte("$MAP03 BLECHR\n"))
+	data := ww.Bytes()
+
+	wad := WADLoader{}
+	err := wad.LoadFromReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wad.ReadWADLumps()
+
+	musicNames := wad.musicLumpNamesFromMapInfo()
+	for _, name := range []string{"WINNOWR", "D_RUNNIN", "BLECHR"} {
+		if !musicNames[name] {
+			t.Errorf("%s is not found, got %v", name, musicNames)
+		}
+	}
+	if len(musicNames) != 3 {
+		t.Errorf("got %v, want only the 3 songs", musicNames)
+	}
+}
@@ -0,0 +1,230 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	GenMIDIMelodicCount    = 128
+	GenMIDIPercussionCount = 47
+	// the percussion instruments play the General MIDI drum notes 35 to 81
+	GenMIDIFirstPercussionNote = 35
+
+	genMIDIInstrumentCount = GenMIDIMelodicCount + GenMIDIPercussionCount
+	genMIDINameSize        = 32
+)
+
+const (
+	GenMIDIFlagFixedPitch  = 0x0001
+	GenMIDIFlagDoubleVoice = 0x0004
+)
+
+const (
+	GenMIDIFormatOP2  = "op2"
+	GenMIDIFormatJSON = "json"
+)
+
+var genMIDIMagic = []byte("#OPL_II#")
+
+// register values written to the OPL chip for one operator
+type OPLOperator struct {
+	Characteristic uint8 `json:"characteristic"` // 0x20: tremolo, vibrato, sustain, KSR, multiplier
+	AttackDecay    uint8 `json:"attackDecay"`    // 0x60
+	SustainRelease uint8 `json:"sustainRelease"` // 0x80
+	Waveform       uint8 `json:"waveform"`       // 0xE0
+	KeyScale       uint8 `json:"keyScale"`       // 0x40 high bits
+	Level          uint8 `json:"level"`          // 0x40 low bits
+}
+
+// fields are in lump order, 16 bytes
+type OPLVoice struct {
+	Modulator  OPLOperator `json:"modulator"`
+	Feedback   uint8       `json:"feedback"` // 0xC0: feedback and connection
+	Carrier    OPLOperator `json:"carrier"`
+	Unused     uint8       `json:"unused"`
+	NoteOffset int16       `json:"noteOffset"`
+}
+
+type genMIDIInstrumentData struct {
+	Flags      uint16
+	FineTuning uint8
+	FixedNote  uint8
+	Voices     [2]OPLVoice
+}
+
+type GenMIDIInstrument struct {
+	Name       string      `json:"name"`
+	Flags      uint16      `json:"flags"`
+	FineTuning uint8       `json:"fineTuning"`
+	FixedNote  uint8       `json:"fixedNote"`
+	Voices     [2]OPLVoice `json:"voices"`
+}
+
+// the DMX OPL bank: 128 General MIDI instruments followed by the percussion ones
+type GenMIDI struct {
+	Instruments []GenMIDIInstrument `json:"instruments"`
+}
+
+func (gi *GenMIDIInstrument) FixedPitch() bool {
+	return gi.Flags&GenMIDIFlagFixedPitch != 0
+}
+
+func (gi *GenMIDIInstrument) DoubleVoice() bool {
+	return gi.Flags&GenMIDIFlagDoubleVoice != 0
+}
+
+// instrument for a MIDI program or, on the percussion channel, a drum note
+func (gm *GenMIDI) Instrument(program int, percussion bool) *GenMIDIInstrument {
+	if percussion {
+		program = GenMIDIMelodicCount + program - GenMIDIFirstPercussionNote
+		if program < GenMIDIMelodicCount || program >= len(gm.Instruments) {
+			return nil
+		}
+	}
+
+	if program < 0 || program >= len(gm.Instruments) {
+		return nil
+	}
+
+	return &gm.Instruments[program]
+}
+
+// 8 bytes magic, the 175 instruments (36 bytes each) and then their 32 bytes names
+func ParseGenMIDI(data []byte) (GenMIDI, error) {
+	var genMIDI GenMIDI
+
+	if len(data) < len(genMIDIMagic) || !bytes.Equal(data[:len(genMIDIMagic)], genMIDIMagic) {
+		return genMIDI, errors.New("[Error] ParseGenMIDI: Missing the #OPL_II# header")
+	}
+
+	instruments := make([]genMIDIInstrumentData, genMIDIInstrumentCount)
+	reader := bytes.NewReader(data[len(genMIDIMagic):])
+
+	err := binary.Read(reader, binary.LittleEndian, &instruments)
+	if err != nil {
+		return genMIDI, errors.New("[Error] ParseGenMIDI: Cannot read the instruments - " + err.Error())
+	}
+
+	names := make([]byte, genMIDIInstrumentCount*genMIDINameSize)
+	readCount, _ := reader.Read(names)
+	if readCount < len(names) {
+		return genMIDI, errors.New("[Error] ParseGenMIDI: Cannot read the instrument names")
+	}
+
+	genMIDI.Instruments = make([]GenMIDIInstrument, genMIDIInstrumentCount)
+	for idx, instrument := range instruments {
+		nameData := names[idx*genMIDINameSize : (idx+1)*genMIDINameSize]
+		if end := bytes.IndexByte(nameData, 0); end >= 0 {
+			nameData = nameData[:end]
+		}
+
+		genMIDI.Instruments[idx] = GenMIDIInstrument{
+			Name:       string(nameData),
+			Flags:      instrument.Flags,
+			FineTuning: instrument.FineTuning,
+			FixedNote:  instrument.FixedNote,
+			Voices:     instrument.Voices,
+		}
+	}
+
+	return genMIDI, nil
+}
+
+// GENMIDI lump data, also the DMXOPL .op2 file
+func (gm *GenMIDI) Bytes() []byte {
+	var buf bytes.Buffer
+	buf.Write(genMIDIMagic)
+
+	for _, instrument := range gm.Instruments {
+		binary.Write(&buf, binary.LittleEndian, genMIDIInstrumentData{
+			Flags:      instrument.Flags,
+			FineTuning: instrument.FineTuning,
+			FixedNote:  instrument.FixedNote,
+			Voices:     instrument.Voices,
+		})
+	}
+
+	for _, instrument := range gm.Instruments {
+		var name [genMIDINameSize]byte
+		copy(name[:genMIDINameSize-1], instrument.Name)
+		buf.Write(name[:])
+	}
+
+	return buf.Bytes()
+}
+
+func (wl *WADLoader) LoadGenMIDI() (GenMIDI, error) {
+	lump, found := wl.findLastLump("GENMIDI")
+	if !found {
+		return GenMIDI{}, errors.New("[Warn] LoadGenMIDI: No GENMIDI lump found")
+	}
+
+	lumpData, err := wl.ReadLumpData(lump)
+	if err != nil {
+		return GenMIDI{}, err
+	}
+
+	return ParseGenMIDI(lumpData)
+}
+
+// .json files get the JSON dump, anything else the .op2 bank
+func GenMIDIFormatFromFilename(filename string) string {
+	if strings.ToLower(filepath.Ext(filename)) == ".json" {
+		return GenMIDIFormatJSON
+	}
+	return GenMIDIFormatOP2
+}
+
+func ExportGenMIDI(genMIDI GenMIDI, filename string, format string) error {
+	var data []byte
+
+	switch format {
+	case GenMIDIFormatOP2:
+		data = genMIDI.Bytes()
+	case GenMIDIFormatJSON:
+		jsonData, err := json.MarshalIndent(genMIDI, "", "  ")
+		if err != nil {
+			return errors.New("[Error] ExportGenMIDI: Cannot encode the instruments - " + err.Error())
+		}
+		data = jsonData
+	default:
+		return errors.New("[Error] ExportGenMIDI: Unknown bank format " + format)
+	}
+
+	err := os.WriteFile(filename, data, 0644)
+	if err != nil {
+		return errors.New("[Error] ExportGenMIDI: Cannot write " + filename + " - " + err.Error())
+	}
+
+	return nil
+}
+
+// the format is detected from the content: the #OPL_II# magic or a JSON dump
+func ImportGenMIDI(filename string) (GenMIDI, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return GenMIDI{}, errors.New("[Error] ImportGenMIDI: Cannot read " + filename + " - " + err.Error())
+	}
+
+	if bytes.HasPrefix(data, genMIDIMagic) {
+		return ParseGenMIDI(data)
+	}
+
+	var genMIDI GenMIDI
+	err = json.Unmarshal(data, &genMIDI)
+	if err != nil {
+		return genMIDI, errors.New("[Error] ImportGenMIDI: " + filename + " is neither an OP2 bank nor a JSON dump - " + err.Error())
+	}
+
+	if len(genMIDI.Instruments) != genMIDIInstrumentCount {
+		return genMIDI, errors.New("[Error] ImportGenMIDI: The bank must have 175 instruments")
+	}
+
+	return genMIDI, nil
+}
@@ -8,7 +8,6 @@ import (
 	"image"
 	"image/color"
 	"image/png"
-	"io"
 	"os"
 )
 
@@ -85,12 +84,25 @@ func (wl *WADLoader) DetectPalettes() ([]Palette, error) {
 		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
 
 		if string(lumpName) == "PLAYPAL" {
-			wl.WADParser.byteReader.Seek(int64(lump.LumpOffset), io.SeekStart)
+			// the profile sets how many palettes the game uses, shorter lumps have less
+			paletteCount := wl.profile().PaletteCount
+			if int(lump.LumpSize)/768 < paletteCount {
+				paletteCount = int(lump.LumpSize) / 768
+			}
+
+			if paletteCount < 1 {
+				return palettes, errors.New("[Error] DetectPalette: The PLAYPAL lump is too short to hold a palette")
+			}
+
+			playpalData, err := wl.WADParser.readAt(int64(lump.LumpOffset), paletteCount*768)
+			if err != nil {
+				return palettes, errors.New("[Error] DetectPalette: Cannot read one of the palettes, aborting palette detection")
+			}
 
-			for i := 0; i < 14; i++ {
+			for i := 0; i < paletteCount; i++ {
 				var p Palette
 
-				errRead := binary.Read(wl.WADParser.byteReader, binary.LittleEndian, &p)
+				errRead := binary.Read(bytes.NewReader(playpalData[i*768:(i+1)*768]), binary.LittleEndian, &p)
 				if errRead != nil {
 					return palettes, errors.New("[Error] DetectPalette: Cannot read one of the palettes, aborting palette detection")
 				}
@@ -124,13 +136,9 @@ func (wl *WADLoader) DetectGraphics() ([]Patch, []Patch, []Flat, error) {
 		return sprites, patches, flats, errors.New("[Warn] DetectGraphics: Cannot get sprite lumps")
 	}
 
-	for _, lump := range spriteLumps {
-		spritePatch, err := wl.parsePatchLump(lump)
-		if err != nil {
-			return sprites, patches, flats, errors.New("[Error] DetectGraphics: Cannot parse a sprite patch lump - " + err.Error())
-		}
-
-		sprites = append(sprites, spritePatch)
+	sprites, err = wl.parsePatchLumps(spriteLumps)
+	if err != nil {
+		return sprites, patches, flats, errors.New("[Error] DetectGraphics: Cannot parse a sprite patch lump - " + err.Error())
 	}
 
 	// Patch sprite loading
@@ -139,13 +147,9 @@ func (wl *WADLoader) DetectGraphics() ([]Patch, []Patch, []Flat, error) {
 		return sprites, patches, flats, errors.New("[Warn] DetectGraphics: Cannot detect patch lumps")
 	}
 
-	for _, lump := range patchLumps {
-		patch, err := wl.parsePatchLump(lump)
-		if err != nil {
-			return sprites, patches, flats, errors.New("[Error] DetectGraphics: Cannot parse a patch lump - " + err.Error())
-		}
-
-		patches = append(patches, patch)
+	patches, err = wl.parsePatchLumps(patchLumps)
+	if err != nil {
+		return sprites, patches, flats, errors.New("[Error] DetectGraphics: Cannot parse a patch lump - " + err.Error())
 	}
 
 	// Flat loading
@@ -154,16 +158,57 @@ func (wl *WADLoader) DetectGraphics() ([]Patch, []Patch, []Flat, error) {
 		return sprites, patches, flats, errors.New("[Warn] DetectGraphics: Cannot detect flat lumps")
 	}
 
-	for _, lump := range flatLumps {
-		flat, err := wl.parseFlatLump(lump)
+	flats, err = wl.parseFlatLumps(flatLumps)
+	if err != nil {
+		return sprites, patches, flats, errors.New("[Error] DetectGraphics: Cannot parse a flat lump - " + err.Error())
+	}
+
+	return sprites, patches, flats, nil
+}
+
+// decodes the lumps in parallel, keeping the directory order
+func (wl *WADLoader) parsePatchLumps(lumps []Lump) ([]Patch, error) {
+	inBoundsLumps := wl.filterDecodableGraphicLumps(lumps, pngPatchSizeError)
+
+	patches := make([]Patch, len(inBoundsLumps))
+
+	err := runWorkers(len(inBoundsLumps), wl.workerCount(), func(idx int) error {
+		patch, err := wl.parsePatchLump(inBoundsLumps[idx])
 		if err != nil {
-			return sprites, patches, flats, errors.New("[Error] DetectGraphics: Cannot parse a patch lump - " + err.Error())
+			return err
 		}
 
-		flats = append(flats, flat)
+		patches[idx] = patch
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
 	}
 
-	return sprites, patches, flats, nil
+	return patches, nil
+}
+
+func (wl *WADLoader) parseFlatLumps(lumps []Lump) ([]Flat, error) {
+	inBoundsLumps := wl.filterDecodableGraphicLumps(lumps, pngFlatSizeError)
+
+	flats := make([]Flat, len(inBoundsLumps))
+
+	err := runWorkers(len(inBoundsLumps), wl.workerCount(), func(idx int) error {
+		flat, err := wl.parseFlatLump(inBoundsLumps[idx])
+		if err != nil {
+			return err
+		}
+
+		flats[idx] = flat
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return flats, nil
 }
 
 func getSpriteLumps(lumps []Lump) ([]Lump, error) {
@@ -240,18 +285,70 @@ func getFlatLumps(lumps []Lump) ([]Lump, error) {
 	return flatLumps, nil
 }
 
-func (wl *WADLoader) parsePatchLump(patchLump Lump) (Patch, error) {
-	wl.WADParser.checkValidByteReader()
+// skips lumps out of the WAD and the PNG graphics (common in PK3s) that cannot be decoded:
+// without a palette to match their colors or with a size checkPNGSize refuses
+func (wl *WADLoader) filterDecodableGraphicLumps(lumps []Lump, checkPNGSize func(width int, height int) error) []Lump {
+	var decodableLumps []Lump
 
-	var patch Patch
+	for _, lump := range lumps {
+		if !wl.IsLumpInBounds(lump) {
+			continue
+		}
 
-	if patchLump.LumpSize < 8 {
-		return patch, errors.New("[Error] parsePatchLump: Provided lump doesn't have enough bytes to parse header")
+		if width, height, isPNG := wl.pngLumpSize(lump); isPNG {
+			lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+			if len(wl.Palettes) < 1 {
+				fmt.Println("[Warn] DetectGraphics: " + lumpName + " is a PNG graphic and there is no palette to match its colors, skipping it")
+				continue
+			}
+
+			if err := checkPNGSize(width, height); err != nil {
+				fmt.Println("[Warn] DetectGraphics: " + lumpName + " " + err.Error() + ", skipping it")
+				continue
+			}
+		}
+
+		decodableLumps = append(decodableLumps, lump)
+	}
+
+	return decodableLumps
+}
+
+func (wl *WADLoader) pngLumpSize(lump Lump) (int, int, bool) {
+	if lump.LumpSize < 24 {
+		return 0, 0, false
 	}
 
-	wl.WADParser.byteReader.Seek(int64(patchLump.LumpOffset), io.SeekStart)
+	header, err := wl.WADParser.readAt(int64(lump.LumpOffset), 24)
+	if err != nil {
+		return 0, 0, false
+	}
 
+	return pngSize(header)
+}
+
+func (wl *WADLoader) parsePatchLump(patchLump Lump) (Patch, error) {
 	lumpName := string(bytes.Trim(patchLump.LumpName[:], "\x00"))
+
+	if patchLump.LumpSize < 8 {
+		return Patch{Name: lumpName}, errors.New("[Error] parsePatchLump: Provided lump doesn't have enough bytes to parse header")
+	}
+
+	lumpData, err := wl.WADParser.readLump(patchLump)
+	if err != nil {
+		return Patch{Name: lumpName}, errors.New("[Error] parsePatchLump: Cannot read lump data of " + lumpName + " - " + err.Error())
+	}
+
+	if isPNGData(lumpData) {
+		return PNGToPatch(lumpName, lumpData, wl.Palettes[0])
+	}
+
+	return parsePatchData(lumpName, lumpData)
+}
+
+func parsePatchData(lumpName string, lumpData []byte) (Patch, error) {
+	var patch Patch
 	patch.Name = lumpName
 
 	var patchHeader struct {
@@ -261,7 +358,9 @@ func (wl *WADLoader) parsePatchLump(patchLump Lump) (Patch, error) {
 		TopOffset  int16
 	}
 
-	errRead := binary.Read(wl.WADParser.byteReader, binary.LittleEndian, &patchHeader)
+	lumpReader := bytes.NewReader(lumpData)
+
+	errRead := binary.Read(lumpReader, binary.LittleEndian, &patchHeader)
 	if errRead != nil {
 		return patch, errors.New("[Error] parsePatchLump: Cannot parse header info of " + lumpName + " - " + errRead.Error())
 	}
@@ -271,24 +370,19 @@ func (wl *WADLoader) parsePatchLump(patchLump Lump) (Patch, error) {
 	patch.LeftOffset = patchHeader.LeftOffset
 	patch.TopOffset = patchHeader.TopOffset
 
-	var patchHeaderPostOffsets []uint32
-	for i := 0; i < int(patch.Width); i++ {
-		var postOffset uint32
-
-		errRead = binary.Read(wl.WADParser.byteReader, binary.LittleEndian, &postOffset)
-		if errRead != nil {
-			return patch, errors.New("[Error] parsePatchLump: Cannot parse a patch post offset of " + lumpName + " - " + errRead.Error())
-		}
+	patchHeaderPostOffsets := make([]uint32, patch.Width)
 
-		patchHeaderPostOffsets = append(patchHeaderPostOffsets, postOffset)
+	errRead = binary.Read(lumpReader, binary.LittleEndian, &patchHeaderPostOffsets)
+	if errRead != nil {
+		return patch, errors.New("[Error] parsePatchLump: Cannot parse a patch post offset of " + lumpName + " - " + errRead.Error())
 	}
 
 	patch.PostOffsets = patchHeaderPostOffsets
 
 	for _, currOffset := range patch.PostOffsets {
-		pPost, err := wl.parsePatchPost(patchLump.LumpOffset + currOffset)
+		pPost, err := parsePatchPost(lumpData, currOffset)
 		if err != nil {
-			return patch, errors.New("[Error] parsePatchLump: Cannot parse a patch post of " + lumpName + " - " + errRead.Error())
+			return patch, errors.New("[Error] parsePatchLump: Cannot parse a patch post of " + lumpName + " - " + err.Error())
 		}
 
 		patch.PatchPosts = append(patch.PatchPosts, pPost)
@@ -297,13 +391,14 @@ func (wl *WADLoader) parsePatchLump(patchLump Lump) (Patch, error) {
 	return patch, nil
 }
 
-func (wl *WADLoader) parsePatchPost(lumpOffset uint32) (PatchPost, error) {
+// postOffset is relative to the start of the patch lump
+func parsePatchPost(lumpData []byte, postOffset uint32) (PatchPost, error) {
 
 	var patchPost PatchPost
-	var currInnerPostOffset uint32 = lumpOffset
+	var currInnerPostOffset uint32 = postOffset
 
 	for {
-		patchPostSeg, currOffset, err := wl.parsePatchPostSegment(currInnerPostOffset)
+		patchPostSeg, nextOffset, err := parsePatchPostSegment(lumpData, currInnerPostOffset)
 
 		if err != nil {
 			return patchPost, errors.New("[Error] parsePatchPost: Cannot parse a patch post segment - " + err.Error())
@@ -314,80 +409,71 @@ func (wl *WADLoader) parsePatchPost(lumpOffset uint32) (PatchPost, error) {
 		}
 
 		patchPost = append(patchPost, patchPostSeg)
-		currInnerPostOffset = uint32(currOffset)
+		currInnerPostOffset = nextOffset
 	}
 
 	return patchPost, nil
 }
 
 func (wl *WADLoader) parseFlatLump(flatLump Lump) (Flat, error) {
-	wl.WADParser.checkValidByteReader()
-
 	var flat Flat
 
-	wl.WADParser.byteReader.Seek(int64(flatLump.LumpOffset), io.SeekStart)
-
 	lumpName := string(bytes.Trim(flatLump.LumpName[:], "\x00"))
 	flat.Name = lumpName
 
-	var flatPixelData [4096]byte
+	if _, _, isPNG := wl.pngLumpSize(flatLump); isPNG {
+		lumpData, err := wl.WADParser.readLump(flatLump)
+		if err != nil {
+			return flat, errors.New("[Error] parseFlatLump: Cannot read lump data of " + lumpName + " - " + err.Error())
+		}
+		return PNGToFlat(lumpName, lumpData, wl.Palettes[0])
+	}
+
+	if flatLump.LumpSize < 4096 {
+		return flat, errors.New("[Error] parseFlatLump: Cannot parse flat lump data - " + lumpName + " - not enough bytes")
+	}
 
-	errRead := binary.Read(wl.WADParser.byteReader, binary.LittleEndian, &flatPixelData)
+	// some ports use bigger flats, only the first 64x64 block is read
+	flatPixelData, errRead := wl.WADParser.readAt(int64(flatLump.LumpOffset), 4096)
 	if errRead != nil {
 		return flat, errors.New("[Error] parseFlatLump: Cannot parse flat lump data - " + lumpName + " - " + errRead.Error())
 	}
 
-	flat.PixelData = flatPixelData
+	copy(flat.PixelData[:], flatPixelData)
 
 	return flat, nil
 }
 
-func (wl *WADLoader) parsePatchPostSegment(offset uint32) (PatchPostSegment, int64, error) {
-	wl.WADParser.checkValidByteReader()
-	wl.WADParser.byteReader.Seek(int64(offset), io.SeekStart)
-
+func parsePatchPostSegment(lumpData []byte, offset uint32) (PatchPostSegment, uint32, error) {
 	var pPost PatchPostSegment
 
-	var patchPostHeaderFields struct {
-		TopOffset  uint8
-		Length     uint8
-		PaddingPre uint8 // ignore data, only use is to move seeker
+	if int(offset) >= len(lumpData) {
+		return pPost, 0, errors.New("[Error] parsePatchPostSegment: Cannot parse patch post header data - offset out of the lump")
 	}
 
-	errRead := binary.Read(wl.WADParser.byteReader, binary.LittleEndian, &patchPostHeaderFields)
-	if errRead != nil {
-		return pPost, 0, errors.New("[Error] parsePatchPostSegment: Cannot parse patch post header data - " + errRead.Error())
-	}
-
-	pPost.TopOffset = patchPostHeaderFields.TopOffset
+	pPost.TopOffset = lumpData[offset]
 	if pPost.TopOffset == 255 {
 		return pPost, 0, nil
 	}
 
-	pPost.Length = patchPostHeaderFields.Length
-
-	pixelData := make([]byte, pPost.Length)
-
-	errRead = binary.Read(wl.WADParser.byteReader, binary.LittleEndian, &pixelData)
-	if errRead != nil {
-		return pPost, 0, errors.New("[Error] parsePatchPostSegment: Cannot parse patch post pixel data - " + errRead.Error())
+	// header is top offset, length and a padding byte
+	if int(offset)+3 > len(lumpData) {
+		return pPost, 0, errors.New("[Error] parsePatchPostSegment: Cannot parse patch post header data - offset out of the lump")
 	}
 
-	pPost.PixelData = pixelData
+	pPost.Length = lumpData[offset+1]
 
-	var paddingPost uint8
-	errRead = binary.Read(wl.WADParser.byteReader, binary.LittleEndian, &paddingPost)
-	if errRead != nil {
-		return pPost, 0, errors.New("[Error] parsePatchPostSegment: Cannot parse patch post segment end padding - " + errRead.Error())
-	}
+	pixelStart := int(offset) + 3
+	pixelEnd := pixelStart + int(pPost.Length)
 
-	// get bytereader's current offset so next segment can be read
-	currOffset, seekErr := wl.WADParser.byteReader.Seek(0, io.SeekCurrent)
-	if seekErr != nil {
-		return pPost, currOffset, errors.New("[Error] parsePatchPostSegment: Cannot get current offset of bytereader - " + seekErr.Error())
+	if pixelEnd+1 > len(lumpData) {
+		return pPost, 0, errors.New("[Error] parsePatchPostSegment: Cannot parse patch post pixel data - post exceeds the lump")
 	}
 
-	return pPost, currOffset, nil
+	pPost.PixelData = lumpData[pixelStart:pixelEnd]
+
+	// skip the end padding byte so next segment can be read
+	return pPost, uint32(pixelEnd + 1), nil
 }
 
 func getSpriteMarkerIndexes(lumps []Lump) (spriteMarkerIndexes, error) {
@@ -493,51 +579,131 @@ func getPatchMarkerIndexes(lumps []Lump) (patchesMarkerIndexes, error) {
 
 func (wl *WADLoader) ExportAllSprites(outputFolder string) error {
 
-	if outputFolder == "" {
-		return errors.New("[Error] createFolder: No folder name specified")
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
 	}
 
-	lastChar := outputFolder[len(outputFolder)-1:]
-	if lastChar == "/" {
-		outputFolder = outputFolder[:len(outputFolder)-1]
-	}
+	palette := wl.renderPalette()
 
-	_, err := os.Stat(outputFolder)
-	if err != nil {
-		err = os.Mkdir(outputFolder, 0755)
-		if err != nil {
-			return errors.New("[Error] createFolder: Cannot create the target folder")
-		}
-	}
+	// the workers would race on the same file for lumps sharing a name, the last one wins like in the game
+	patchName := func(patch Patch) string { return patch.Name }
+	sprites := lastByName(wl.Sprites, patchName)
+	patches := lastByName(wl.Patches, patchName)
+	graphics := lastByName(wl.Graphics, patchName)
+	screens := lastByName(wl.Screens, func(screen RawScreen) string { return screen.Name })
+	flats := lastByName(wl.Flats, func(flat Flat) string { return flat.Name })
 
 	// Sprite exporting
-	for _, sprite := range wl.Sprites {
-		exportErr := ExportSprite(sprite, wl.Palettes[0], outputFolder)
+	err = runWorkers(len(sprites), wl.workerCount(), func(idx int) error {
+		sprite := sprites[idx]
+		exportErr := ExportSprite(sprite, palette, outputFolder)
 		if exportErr != nil {
 			return errors.New("[Error] ExportAllSprites: Cannot export sprite - " + sprite.Name + " - " + exportErr.Error())
 		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Patch sprite exporting
-	for _, patchSprites := range wl.Patches {
-		exportErr := ExportSprite(patchSprites, wl.Palettes[0], outputFolder)
+	err = runWorkers(len(patches), wl.workerCount(), func(idx int) error {
+		patchSprites := patches[idx]
+		exportErr := ExportSprite(patchSprites, palette, outputFolder)
 		if exportErr != nil {
 			return errors.New("[Error] ExportAllSprites: Cannot export patch sprite - " + patchSprites.Name + " - " + exportErr.Error())
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Loose graphics exporting
+	err = runWorkers(len(graphics), wl.workerCount(), func(idx int) error {
+		graphic := graphics[idx]
+		exportErr := ExportSprite(graphic, palette, outputFolder)
+		if exportErr != nil {
+			return errors.New("[Error] ExportAllSprites: Cannot export graphic - " + graphic.Name + " - " + exportErr.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Raw screen exporting
+	err = runWorkers(len(screens), wl.workerCount(), func(idx int) error {
+		screen := screens[idx]
+		exportErr := ExportRawScreen(screen, palette, outputFolder)
+		if exportErr != nil {
+			return errors.New("[Error] ExportAllSprites: Cannot export screen - " + screen.Name + " - " + exportErr.Error())
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
 	// Flat exporting
-	for _, flat := range wl.Flats {
-		exportErr := ExportFlat(flat, wl.Palettes[0], outputFolder)
+	return runWorkers(len(flats), wl.workerCount(), func(idx int) error {
+		flat := flats[idx]
+		exportErr := ExportFlat(flat, palette, outputFolder)
 		if exportErr != nil {
 			return errors.New("[Error] ExportAllSprites: Cannot export flat - " + flat.Name + " - " + exportErr.Error())
 		}
+		return nil
+	})
+}
+
+// keeps the last entry of every name in its original place
+func lastByName[T any](items []T, name func(T) string) []T {
+	lastIdx := make(map[string]int, len(items))
+	for idx, item := range items {
+		lastIdx[name(item)] = idx
 	}
 
-	return nil
+	unique := make([]T, 0, len(lastIdx))
+	for idx, item := range items {
+		if lastIdx[name(item)] == idx {
+			unique = append(unique, item)
+		}
+	}
+
+	return unique
 }
 
 func ExportSprite(sprite Patch, palette Palette, outputFolder string) error {
+	spriteImg := PatchToImage(sprite, palette)
+
+	spriteFile, err := os.Create(outputFolder + "/" + sprite.Name + ".png")
+	if err != nil {
+		return errors.New("[Error] ExportSprite: Cannot create the target file for the sprite - " + sprite.Name + " - " + err.Error())
+	}
+
+	defer spriteFile.Close()
+	png.Encode(spriteFile, spriteImg)
+
+	return nil
+}
+
+func ExportFlat(flat Flat, palette Palette, outputFolder string) error {
+	flatImg := FlatToImage(flat, palette)
+
+	flatFile, err := os.Create(outputFolder + "/" + flat.Name + ".png")
+	if err != nil {
+		return errors.New("[Error] ExportFlat: Cannot create the target file for the flat - " + flat.Name + " - " + err.Error())
+	}
+
+	defer flatFile.Close()
+	png.Encode(flatFile, flatImg)
+
+	return nil
+}
+
+// pixels not covered by any post are left transparent
+func PatchToImage(sprite Patch, palette Palette) *image.RGBA {
 	spriteImg := image.NewRGBA(image.Rect(0, 0, int(sprite.Width), int(sprite.Height)))
 
 	for idx, post := range sprite.PatchPosts {
@@ -552,18 +718,10 @@ func ExportSprite(sprite Patch, palette Palette, outputFolder string) error {
 		}
 	}
 
-	spriteFile, err := os.Create(outputFolder + "/" + sprite.Name + ".png")
-	if err != nil {
-		return errors.New("[Error] ExportSprite: Cannot create the target file for the sprite - " + sprite.Name + " - " + err.Error())
-	}
-
-	defer spriteFile.Close()
-	png.Encode(spriteFile, spriteImg)
-
-	return nil
+	return spriteImg
 }
 
-func ExportFlat(flat Flat, palette Palette, outputFolder string) error {
+func FlatToImage(flat Flat, palette Palette) *image.RGBA {
 	flatImg := image.NewRGBA(image.Rect(0, 0, 64, 64))
 
 	for y := 0; y < 64; y++ {
@@ -573,13 +731,5 @@ func ExportFlat(flat Flat, palette Palette, outputFolder string) error {
 		}
 	}
 
-	flatFile, err := os.Create(outputFolder + "/" + flat.Name + ".png")
-	if err != nil {
-		return errors.New("[Error] ExportFlat: Cannot create the target file for the flat - " + flat.Name + " - " + err.Error())
-	}
-
-	defer flatFile.Close()
-	png.Encode(flatFile, flatImg)
-
-	return nil
+	return flatImg
 }
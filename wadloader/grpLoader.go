@@ -0,0 +1,205 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+)
+
+// Build engine GRP archives: "KenSilverman" header, a directory of 16 bytes entries
+// right after it and the entries data stored one after another in the same order
+const grpEntryByteSize = 16
+
+type GRPLoader struct {
+	GRPFilename string
+	GRPReader   io.ReaderAt
+	GRPSize     int64
+	GRPHeader   GRPHeader
+	Entries     []GRPEntry
+
+	grpCloser io.Closer
+}
+
+type GRPHeader struct {
+	Signature  [12]byte
+	EntryCount uint32
+}
+
+type GRPEntry struct {
+	EntryName [12]byte
+	EntrySize uint32
+
+	// not stored in the archive, computed from the entry sizes
+	EntryOffset int64
+}
+
+func (e GRPEntry) Name() string {
+	nameEnd := bytes.IndexByte(e.EntryName[:], 0)
+	if nameEnd == -1 {
+		nameEnd = len(e.EntryName)
+	}
+	return strings.TrimRight(string(e.EntryName[:nameEnd]), " ")
+}
+
+func (gl *GRPLoader) OpenAndLoad(grpFilename string) error {
+	gl.GRPFilename = grpFilename
+
+	grpFile, err := os.Open(grpFilename)
+	if err != nil {
+		return errors.New("[Error] OpenAndLoad: Couldn't open the GRP file - " + err.Error())
+	}
+
+	fileInfo, err := grpFile.Stat()
+	if err != nil {
+		grpFile.Close()
+		return errors.New("[Error] OpenAndLoad: Couldn't get the GRP file size - " + err.Error())
+	}
+
+	err = gl.LoadFromReaderAt(grpFile, fileInfo.Size())
+	if err != nil {
+		grpFile.Close()
+		return err
+	}
+
+	gl.grpCloser = grpFile
+
+	return nil
+}
+
+func (gl *GRPLoader) Close() error {
+	if gl.grpCloser == nil {
+		return nil
+	}
+
+	err := gl.grpCloser.Close()
+	gl.grpCloser = nil
+
+	return err
+}
+
+func (gl *GRPLoader) LoadFromReaderAt(r io.ReaderAt, size int64) error {
+	if size < 16 {
+		return errors.New("[Error] LoadFromReaderAt: Not enough data to read the GRP header")
+	}
+
+	gl.GRPReader = r
+	gl.GRPSize = size
+
+	err := binary.Read(io.NewSectionReader(r, 0, 16), binary.LittleEndian, &gl.GRPHeader)
+	if err != nil {
+		return errors.New("[Error] LoadFromReaderAt: Invalid data when reading the GRP Header - " + err.Error())
+	}
+
+	if string(gl.GRPHeader.Signature[:]) != "KenSilverman" {
+		return errors.New("[Error] LoadFromReaderAt: Not a GRP file")
+	}
+
+	return gl.readEntries()
+}
+
+func (gl *GRPLoader) readEntries() error {
+	dirSize := int64(gl.GRPHeader.EntryCount) * grpEntryByteSize
+
+	if 16+dirSize > gl.GRPSize {
+		return errors.New("[Error] readEntries: The GRP directory points outside of the file")
+	}
+
+	rawEntries := make([]struct {
+		EntryName [12]byte
+		EntrySize uint32
+	}, gl.GRPHeader.EntryCount)
+
+	err := binary.Read(io.NewSectionReader(gl.GRPReader, 16, dirSize), binary.LittleEndian, &rawEntries)
+	if err != nil {
+		return errors.New("[Error] readEntries: Invalid data when reading the GRP directory - " + err.Error())
+	}
+
+	gl.Entries = make([]GRPEntry, len(rawEntries))
+	entryOffset := 16 + dirSize
+
+	for idx, rawEntry := range rawEntries {
+		gl.Entries[idx] = GRPEntry{
+			EntryName:   rawEntry.EntryName,
+			EntrySize:   rawEntry.EntrySize,
+			EntryOffset: entryOffset,
+		}
+		entryOffset += int64(rawEntry.EntrySize)
+	}
+
+	return nil
+}
+
+func (gl *GRPLoader) ReadEntryData(entry GRPEntry) ([]byte, error) {
+	if entry.EntryOffset+int64(entry.EntrySize) > gl.GRPSize {
+		return nil, errors.New("[Error] ReadEntryData: " + entry.Name() + " points outside of the GRP file")
+	}
+
+	data := make([]byte, entry.EntrySize)
+
+	_, err := io.ReadFull(io.NewSectionReader(gl.GRPReader, entry.EntryOffset, int64(entry.EntrySize)), data)
+	if err != nil {
+		return nil, errors.New("[Error] ReadEntryData: Cannot read " + entry.Name() + " - " + err.Error())
+	}
+
+	return data, nil
+}
+
+// GRP entries have no folders, the names are still checked like PAK paths
+func (gl *GRPLoader) ExtractAll(outputFolder string) error {
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range gl.Entries {
+		err := extractArchiveEntry(outputFolder, entry.Name(), func() ([]byte, error) {
+			return gl.ReadEntryData(entry)
+		})
+		if err != nil {
+			return errors.New("[Error] ExtractAll: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (gl *GRPLoader) FindEntry(name string) (GRPEntry, bool) {
+	for _, entry := range gl.Entries {
+		if strings.EqualFold(entry.Name(), name) {
+			return entry, true
+		}
+	}
+
+	return GRPEntry{}, false
+}
+
+// the Build palette is the first 768 bytes of PALETTE.DAT
+func (gl *GRPLoader) LoadPalette() (Palette, error) {
+	entry, found := gl.FindEntry("PALETTE.DAT")
+	if !found {
+		return Palette{}, errors.New("[Error] LoadPalette: No PALETTE.DAT entry in the GRP")
+	}
+
+	data, err := gl.ReadEntryData(entry)
+	if err != nil {
+		return Palette{}, err
+	}
+
+	return PaletteFromVGAData(data)
+}
+
+func (gl *GRPLoader) ArtEntries() []GRPEntry {
+	var artEntries []GRPEntry
+
+	for _, entry := range gl.Entries {
+		if strings.EqualFold(path.Ext(entry.Name()), ".art") {
+			artEntries = append(artEntries, entry)
+		}
+	}
+
+	return artEntries
+}
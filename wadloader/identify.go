@@ -0,0 +1,354 @@
+package wadloader
+
+import (
+	"bytes"
+	"crypto/md5"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"io"
+	"regexp"
+	"strings"
+)
+
+const (
+	GameUnknown   = "Unknown"
+	GameDoom      = "Doom"
+	GameDoom2     = "Doom II"
+	GameTNT       = "Final Doom: TNT Evilution"
+	GamePlutonia  = "Final Doom: The Plutonia Experiment"
+	GameFreedoom1 = "Freedoom: Phase 1"
+	GameFreedoom2 = "Freedoom: Phase 2"
+	GameFreeDM    = "FreeDM"
+	GameHeretic   = "Heretic"
+	GameHexen     = "Hexen"
+	GameStrife    = "Strife"
+	GameChex      = "Chex Quest"
+)
+
+const (
+	IdentifiedByHash  = "MD5/SHA-1"
+	IdentifiedByLumps = "lumps"
+	IdentifiedByMaps  = "maps and lumps"
+)
+
+type WADIdentity struct {
+	Game    string
+	Release string
+	// hash when the whole file matched a known release, lumps when guessed from its contents
+	Method string
+	// only set for IWADs with the size of a known release, the others aren't hashed
+	MD5  string
+	SHA1 string
+	// false for PWADs, Game is then the IWAD the PWAD was made for
+	IsIWAD bool
+}
+
+func (id WADIdentity) String() string {
+	if id.Release == "" {
+		return id.Game
+	}
+	return id.Game + " (" + id.Release + ")"
+}
+
+type knownIWAD struct {
+	game    string
+	release string
+	size    int64
+	md5     string
+	sha1    string
+}
+
+// file size, MD5 and SHA-1 of the retail IWADs, Freedoom changes with each release so it's only identified by its lumps
+var knownIWADs = []knownIWAD{
+	{GameDoom, "Shareware v1.9", 4196020, "f0cefca49926d00903cf57551d901abe", "5b2e249b9c5133ec987b3ea77596381dc0d6bc1d"},
+	{GameDoom, "Registered v1.9", 11159840, "1cd63c5ddff1bf8ce844237f580e9cf3", "7742089b4468a736cadb659a7deca3320fe6dcbd"},
+	{GameDoom, "The Ultimate Doom v1.9", 12408292, "c4fe9fd920207691a9f493668e0a2083", "9b07b02ab3c275a6a7570c3f73cc20d63a0e3833"},
+	{GameDoom, "The Ultimate Doom BFG Edition", 12487824, "fb35c4a5a9fd49ec29ab6e900572c524", "117015379c529573510be08cf59810aa10bb934e"},
+	{GameDoom2, "v1.9", 14604584, "25e1459ca71d321525f84628f45ca8cd", "7ec7652fcfce8ddc6e801839291f0e28ef1d5ae7"},
+	{GameDoom2, "BFG Edition", 14691821, "c3bea40570c23e511a7ed3ebcd9865f7", "a59548125f59f6aa1a41c22f615557d3dd2e85a9"},
+	{GameTNT, "v1.9", 18195736, "4e158d9953c79ccf97bd0663244cc6b6", "9fbc66aedef7fe3bae0986cdb9323d2b8db4c9d3"},
+	{GamePlutonia, "v1.9", 18240172, "75c8cf89566741fa9d22447604053bd7", "90361e2a538d2388506657252ae41aceeb1ba360"},
+	{GameHeretic, "Registered v1.3", 11096488, "66d686b1ed6d35ff103f15dbd30e0341", "f489d479371df32f6d280a0cb23b59a35ba2b833"},
+	{GameHexen, "v1.1", 20083672, "abb033caf81e26f12a2103e1fa25453f", "4b53832f0733c1e29e5f1de2428e5475e891af29"},
+	{GameStrife, "v1.2", 28377364, "2fed2031a5b03892106e0f117f17901f", "64c13b951a845ca7f8081f68138a6181557458d1"},
+	{GameChex, "v1.0", 12361532, "25485721882b050afa96a56e5758dd52", "eca9cff1014ce5081804e193588d96c6ddb35432"},
+}
+
+// hashing reads the whole file, so it's only done when the size already matches a known release
+func isKnownIWADSize(size int64) bool {
+	for _, known := range knownIWADs {
+		if known.size == size {
+			return true
+		}
+	}
+	return false
+}
+
+// a match on either hash is enough
+func findKnownIWAD(size int64, md5Hash string, sha1Hash string) (knownIWAD, bool) {
+	for _, known := range knownIWADs {
+		if known.size == size && (known.md5 == md5Hash || known.sha1 == sha1Hash) {
+			return known, true
+		}
+	}
+	return knownIWAD{}, false
+}
+
+var (
+	doomMapNameRegex = regexp.MustCompile(`^E(\d)M(\d)$`)
+	mapxxNameRegex   = regexp.MustCompile(`^MAP(\d\d)$`)
+)
+
+// IWADs with the size of a known release are looked up by their MD5 or SHA-1 first and guessed from their lumps otherwise,
+// PWADs get the IWAD they need guessed from their maps and resources
+func (wl *WADLoader) Identify() (WADIdentity, error) {
+	var identity WADIdentity
+
+	if len(wl.WADLumps) < 1 {
+		return identity, errors.New("[Error] Identify: No lumps loaded")
+	}
+
+	identity.IsIWAD = string(wl.WADHeader.WadType[:]) == "IWAD"
+
+	if identity.IsIWAD && isKnownIWADSize(wl.WADSize) {
+		md5Hash, sha1Hash, err := wl.hashWAD()
+		if err != nil {
+			return identity, err
+		}
+
+		identity.MD5 = md5Hash
+		identity.SHA1 = sha1Hash
+
+		if known, found := findKnownIWAD(wl.WADSize, md5Hash, sha1Hash); found {
+			identity.Game = known.game
+			identity.Release = known.release
+			identity.Method = IdentifiedByHash
+			return identity, nil
+		}
+	}
+
+	lumpNames := make(map[string]bool, len(wl.WADLumps))
+	for _, lump := range wl.WADLumps {
+		lumpNames[string(bytes.Trim(lump.LumpName[:], "\x00"))] = true
+	}
+
+	identity.Method = IdentifiedByLumps
+
+	if identity.IsIWAD {
+		identity.Game, identity.Release = identifyIWADFromLumps(lumpNames)
+		return identity, nil
+	}
+
+	mapScores := wl.scoreMapResources()
+	if len(mapScores) > 0 {
+		identity.Method = IdentifiedByMaps
+	}
+
+	identity.Game = identifyPWADTarget(lumpNames, mapScores)
+
+	return identity, nil
+}
+
+// MD5 and SHA-1 in a single read of the file
+func (wl *WADLoader) hashWAD() (string, string, error) {
+	md5Hash, sha1Hash := md5.New(), sha1.New()
+
+	_, err := io.Copy(io.MultiWriter(md5Hash, sha1Hash), io.NewSectionReader(wl.WADReader, 0, wl.WADSize))
+	if err != nil {
+		return "", "", errors.New("[Error] hashWAD: Cannot read the WAD data - " + err.Error())
+	}
+
+	return hex.EncodeToString(md5Hash.Sum(nil)), hex.EncodeToString(sha1Hash.Sum(nil)), nil
+}
+
+// same idea as the IWADINFO lump checks of the source ports, most specific games first
+func identifyIWADFromLumps(lumpNames map[string]bool) (string, string) {
+	hasBFGMenu := lumpNames["DMENUPIC"]
+
+	switch {
+	case lumpNames["FREEDM"]:
+		return GameFreeDM, ""
+	case lumpNames["FREEDOOM"] && lumpNames["MAP01"]:
+		return GameFreedoom2, ""
+	case lumpNames["FREEDOOM"]:
+		return GameFreedoom1, ""
+	case lumpNames["ENDSTRF"]:
+		return GameStrife, ""
+	case lumpNames["W94_1"]:
+		return GameChex, ""
+	case lumpNames["MAP01"] && lumpNames["WINNOWR"]:
+		if lumpNames["MAP41"] {
+			return GameHexen, "Registered"
+		}
+		return GameHexen, "Demo"
+	case lumpNames["E1M1"] && lumpNames["MUS_E1M1"]:
+		if lumpNames["E4M1"] {
+			return GameHeretic, "Shadow of the Serpent Riders"
+		}
+		if lumpNames["E2M1"] {
+			return GameHeretic, "Registered"
+		}
+		return GameHeretic, "Shareware"
+	case lumpNames["MAP01"] && lumpNames["REDTNT2"]:
+		return GameTNT, ""
+	case lumpNames["MAP01"] && lumpNames["CAMO1"]:
+		return GamePlutonia, ""
+	case lumpNames["MAP01"]:
+		if hasBFGMenu {
+			return GameDoom2, "BFG Edition"
+		}
+		return GameDoom2, ""
+	case lumpNames["E1M1"]:
+		if hasBFGMenu {
+			return GameDoom, "The Ultimate Doom BFG Edition"
+		}
+		if lumpNames["E4M1"] {
+			return GameDoom, "The Ultimate Doom"
+		}
+		if lumpNames["E2M1"] {
+			return GameDoom, "Registered"
+		}
+		return GameDoom, "Shareware"
+	}
+
+	return GameUnknown, ""
+}
+
+// maps names and resources that only exist in one game decide, ExMy maps go to Doom unless
+// Heretic music, episodes or things are there and MAPxx ones to Doom II unless the things or
+// textures they use belong to another game
+func identifyPWADTarget(lumpNames map[string]bool, mapScores map[string]int) string {
+	hasMAPxx, hasHereticEpisodes, hasHereticMusic := false, false, false
+
+	for name := range lumpNames {
+		if mapxxNameRegex.MatchString(name) {
+			hasMAPxx = true
+		}
+
+		if match := doomMapNameRegex.FindStringSubmatch(name); match != nil && (match[1] == "5" || match[1] == "6") {
+			hasHereticEpisodes = true
+		}
+
+		if strings.HasPrefix(name, "MUS_") {
+			hasHereticMusic = true
+		}
+	}
+
+	// Hexen maps name their songs in MAPINFO/SNDINFO instead of using D_ lumps
+	hasHexenMusic := lumpNames["MAPINFO"] && lumpNames["SNDINFO"] && !hasDoomMusic(lumpNames)
+
+	switch {
+	case lumpNames["ENDSTRF"], lumpNames["RGELOGO"]:
+		return GameStrife
+	case hasMAPxx && mapScores[GameStrife] > mapScores[GameDoom] && mapScores[GameStrife] > mapScores[GameHexen]:
+		return GameStrife
+	case hasMAPxx && lumpNames["BEHAVIOR"] && (hasHexenMusic || mapScores[GameHexen] > mapScores[GameDoom]):
+		return GameHexen
+	case hasMAPxx && mapScores[GameTNT] > mapScores[GamePlutonia]:
+		return GameTNT
+	case hasMAPxx && mapScores[GamePlutonia] > 0:
+		return GamePlutonia
+	case hasMAPxx:
+		return GameDoom2
+	case hasHereticEpisodes, hasHereticMusic, mapScores[GameHeretic] > mapScores[GameDoom]:
+		return GameHeretic
+	case hasAnyExMy(lumpNames):
+		return GameDoom
+	}
+
+	return GameUnknown
+}
+
+// things counted for every game listing their type, wall textures and flats for the game they belong to
+func (wl *WADLoader) scoreMapResources() map[string]int {
+	scores := make(map[string]int)
+
+	for _, rawMap := range wl.DetectMaps() {
+		isHexenFormat := false
+		for _, lump := range rawMap.Lumps {
+			if string(bytes.Trim(lump.LumpName[:], "\x00")) == "BEHAVIOR" {
+				isHexenFormat = true
+			}
+		}
+
+		for _, lump := range rawMap.Lumps {
+			switch string(bytes.Trim(lump.LumpName[:], "\x00")) {
+			case "THINGS":
+				var things []Thing
+				if isHexenFormat {
+					things = hexenThingsToThings(wl.WADParser.parseMapHexenThings(lump))
+				} else {
+					things = wl.WADParser.parseMapThings(lump)
+				}
+				for _, thing := range things {
+					for _, table := range gameThingTypes {
+						if _, found := table.thingTypes[thing.Type]; found {
+							scores[table.game]++
+						}
+					}
+				}
+			case "SIDEDEFS":
+				for _, sidedef := range wl.WADParser.parseMapSidedefs(lump) {
+					for _, texture := range [][8]byte{sidedef.UpperTexture, sidedef.LowerTexture, sidedef.MiddleTexture} {
+						if game := textureGame(texture); game != "" {
+							scores[game]++
+						}
+					}
+				}
+			case "SECTORS":
+				for _, sector := range wl.WADParser.parseMapSectors(lump) {
+					for _, flat := range [][8]byte{sector.FloorTexture, sector.CeilingTexture} {
+						if game := textureGame(flat); game != "" {
+							scores[game]++
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return scores
+}
+
+var gameThingTypes = []struct {
+	game       string
+	thingTypes map[uint16]ThingInfo
+}{
+	{GameDoom, doomThingTypes},
+	{GameHeretic, hereticThingTypes},
+	{GameHexen, hexenThingTypes},
+	{GameStrife, strifeThingTypes},
+}
+
+// Plutonia's own textures start with A- and TNT ones carry TNT in their name
+func textureGame(name [8]byte) string {
+	textureName := strings.ToUpper(string(bytes.Trim(name[:], "\x00")))
+
+	switch {
+	case strings.HasPrefix(textureName, "A-"):
+		return GamePlutonia
+	case strings.Contains(textureName, "TNT"):
+		return GameTNT
+	}
+
+	return ""
+}
+
+func hasDoomMusic(lumpNames map[string]bool) bool {
+	for name := range lumpNames {
+		if strings.HasPrefix(name, "D_") {
+			return true
+		}
+	}
+	return false
+}
+
+func hasAnyExMy(lumpNames map[string]bool) bool {
+	for name := range lumpNames {
+		if doomMapNameRegex.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
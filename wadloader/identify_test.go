@@ -0,0 +1,77 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// a PWAD with one map placing the things and using the texture on every wall and flat
+func buildIdentifyTestPWAD(mapName string, thingTypes []uint16, texture string) []byte {
+	var things, sidedefs, sectors bytes.Buffer
+
+	for _, thingType := range thingTypes {
+		binary.Write(&things, binary.LittleEndian, Thing{Type: thingType})
+	}
+
+	var textureName [8]byte
+	copy(textureName[:], texture)
+	binary.Write(&sidedefs, binary.LittleEndian, Sidedef{UpperTexture: textureName, LowerTexture: textureName, MiddleTexture: textureName})
+	binary.Write(&sectors, binary.LittleEndian, Sector{FloorTexture: textureName, CeilingTexture: textureName})
+
+	var ww WADWriter
+	ww.AddMarker(mapName)
+	for _, lumpName := range MapLumpsNames {
+		switch lumpName {
+		case "THINGS":
+			ww.AddLump(lumpName, things.Bytes())
+		case "SIDEDEFS":
+			ww.AddLump(lumpName, sidedefs.Bytes())
+		case "SECTORS":
+			ww.AddLump(lumpName, sectors.Bytes())
+		default:
+			ww.AddLump(lumpName, []byte{0, 0, 0, 0})
+		}
+	}
+
+	return ww.Bytes()
+}
+
+func TestIdentifyPWADFromMaps(t *testing.T) {
+	doomThings := []uint16{1, 3001, 3004, 2001, 2011}
+	hereticThings := []uint16{1, 15, 45, 46, 70, 73}
+
+	cases := []struct {
+		mapName    string
+		thingTypes []uint16
+		texture    string
+		want       string
+	}{
+		{"MAP01", doomThings, "STARTAN3", GameDoom2},
+		{"MAP01", doomThings, "A-BRICK1", GamePlutonia},
+		{"MAP01", doomThings, "BTNTMETL", GameTNT},
+		{"E1M1", doomThings, "STARTAN3", GameDoom},
+		{"E1M1", hereticThings, "GRSTNPB", GameHeretic},
+	}
+
+	for _, c := range cases {
+		data := buildIdentifyTestPWAD(c.mapName, c.thingTypes, c.texture)
+
+		wad := WADLoader{}
+		err := wad.LoadFromReaderAt(bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wad.ReadWADLumps()
+
+		identity, err := wad.Identify()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if identity.IsIWAD || identity.Method != IdentifiedByMaps || identity.Game != c.want {
+			t.Errorf("%s with %s: made for %s (IWAD %v, by %s), want %s from the maps",
+				c.mapName, c.texture, identity.Game, identity.IsIWAD, identity.Method, c.want)
+		}
+	}
+}
@@ -90,3 +90,112 @@ func DumpSongNamesToTextFile(filename string, songs []MusicLump) {
 
 	fmt.Println("[Info] Song list dumped into", filename)
 }
+
+func DumpLumpIntegrityToTextFile(filename string, report LumpIntegrityReport) {
+	os.Remove(filename)
+	file, err := os.Create(filename)
+
+	if err != nil {
+		fmt.Println("[Error] Cannot create a file called", filename, err)
+		return
+	}
+
+	defer file.Close()
+
+	var errWrite error
+
+	for _, line := range formatLumpIntegrity(report) {
+		_, errWrite = file.WriteString(line + "\n")
+	}
+
+	if errWrite != nil {
+		fmt.Println("[Error] Cannot add integrity data to dump file", filename, errWrite)
+		return
+	}
+
+	fmt.Println("[Info] Lump integrity report dumped into", filename)
+}
+
+func DumpWAD2EntriesToTextFile(filename string, entries []WAD2Entry) {
+	os.Remove(filename)
+	file, err := os.Create(filename)
+
+	if err != nil {
+		fmt.Println("[Error] Cannot create a file called", filename, err)
+		return
+	}
+
+	defer file.Close()
+
+	var errWrite error
+
+	_, errWrite = file.WriteString("Entry name | Type | Size (bytes)\n")
+
+	for _, entry := range entries {
+		outStr := fmt.Sprintf("%s | 0x%02X | %v \n", entry.Name(), entry.Type, entry.Size)
+		_, errWrite = file.WriteString(outStr)
+	}
+
+	if errWrite != nil {
+		fmt.Println("[Error] Cannot add entry data to dump file", filename, errWrite)
+		return
+	}
+
+	fmt.Println("[Info] Entries dumped into", filename)
+}
+
+func DumpPAKEntriesToTextFile(filename string, entries []PAKEntry) {
+	os.Remove(filename)
+	file, err := os.Create(filename)
+
+	if err != nil {
+		fmt.Println("[Error] Cannot create a file called", filename, err)
+		return
+	}
+
+	defer file.Close()
+
+	var errWrite error
+
+	_, errWrite = file.WriteString("Entry path | Size (bytes)\n")
+
+	for _, entry := range entries {
+		outStr := fmt.Sprintf("%s | %v \n", entry.Name(), entry.EntrySize)
+		_, errWrite = file.WriteString(outStr)
+	}
+
+	if errWrite != nil {
+		fmt.Println("[Error] Cannot add entry data to dump file", filename, errWrite)
+		return
+	}
+
+	fmt.Println("[Info] Entries dumped into", filename)
+}
+
+func DumpGRPEntriesToTextFile(filename string, entries []GRPEntry) {
+	os.Remove(filename)
+	file, err := os.Create(filename)
+
+	if err != nil {
+		fmt.Println("[Error] Cannot create a file called", filename, err)
+		return
+	}
+
+	defer file.Close()
+
+	var errWrite error
+
+	_, errWrite = file.WriteString("Entry name | Size (bytes)\n")
+
+	for _, entry := range entries {
+		outStr := fmt.Sprintf("%s | %v \n", entry.Name(), entry.EntrySize)
+		_, errWrite = file.WriteString(outStr)
+	}
+
+	if errWrite != nil {
+		fmt.Println("[Error] Cannot add entry data to dump file", filename, errWrite)
+		return
+	}
+
+	fmt.Println("[Info] Entries dumped into", filename)
+}
@@ -1,7 +1,9 @@
 package wadloader
 
 import (
+	"bytes"
 	"fmt"
+	"sort"
 )
 
 func PrintMapNames(maps []Map) {
@@ -17,3 +19,127 @@ func PrintSongNames(songs []MusicLump) {
 		fmt.Println(m.name + " | " + m.format)
 	}
 }
+
+func PrintSongAnalysis(analyses []SongAnalysis) {
+	fmt.Println("Song analysis | Format | Duration (seconds) | Notes (percussion)")
+	for _, analysis := range analyses {
+		fmt.Printf("%s | %s | %.2f | %v (%v)\n", analysis.Name, analysis.Format, analysis.Duration, analysis.NoteCount, analysis.PercussionNotes)
+
+		if analysis.Format == "MUS" {
+			fmt.Println("  Tempo: fixed 140 Hz ticks")
+		}
+		for _, change := range analysis.TempoChanges {
+			fmt.Printf("  Tempo: %.1f BPM at %.2f seconds\n", change.BPM, change.Time)
+		}
+
+		if len(analysis.Channels) > 0 {
+			fmt.Printf("  Channels: %v\n", analysis.Channels)
+		}
+		for _, channel := range analysis.Channels {
+			if programs, found := analysis.Programs[channel]; found {
+				fmt.Printf("  Channel %v programs: %v\n", channel, programs)
+			}
+		}
+		if len(analysis.PercussionKeys) > 0 {
+			fmt.Printf("  Percussion notes: %v\n", analysis.PercussionKeys)
+		}
+
+		if analysis.Loopable() {
+			fmt.Println("  Loops cleanly")
+		}
+		for _, problem := range analysis.LoopProblems {
+			fmt.Println("  Loop: " + problem)
+		}
+
+		for _, problem := range analysis.Problems {
+			fmt.Println("  Malformed: " + problem)
+		}
+	}
+}
+
+func PrintPCSpeakerSounds(sounds []PCSpeakerSound) {
+	fmt.Println("PC speaker sound | Duration (seconds)")
+	for _, s := range sounds {
+		fmt.Printf("%s | %.2f\n", s.name, s.Duration())
+	}
+}
+
+func PrintDehackedReport(patch DehackedPatch) {
+	fmt.Printf("DeHackEd patch | Doom version %v | Patch format %v\n", patch.DoomVersion, patch.PatchFormat)
+	for _, line := range patch.Report() {
+		fmt.Println(line)
+	}
+	for _, problem := range patch.Problems {
+		fmt.Println("[Warn] " + problem)
+	}
+}
+
+func PrintLumpIntegrity(report LumpIntegrityReport) {
+	for _, line := range formatLumpIntegrity(report) {
+		fmt.Println(line)
+	}
+}
+
+func PrintLumps(lumps WADLumps) {
+	fmt.Println("Lump name | Size (bytes)")
+	for _, lump := range lumps {
+		fmt.Printf("%s | %v\n", bytes.Trim(lump.LumpName[:], "\x00"), lump.LumpSize)
+	}
+}
+
+func PrintWAD2Entries(entries []WAD2Entry) {
+	fmt.Println("Entry name | Type | Size (bytes)")
+	for _, entry := range entries {
+		fmt.Printf("%s | 0x%02X | %v\n", entry.Name(), entry.Type, entry.Size)
+	}
+}
+
+func PrintPAKEntries(entries []PAKEntry) {
+	fmt.Println("Entry path | Size (bytes)")
+	for _, entry := range entries {
+		fmt.Printf("%s | %v\n", entry.Name(), entry.EntrySize)
+	}
+}
+
+func PrintGRPEntries(entries []GRPEntry) {
+	fmt.Println("Entry name | Size (bytes)")
+	for _, entry := range entries {
+		fmt.Printf("%s | %v\n", entry.Name(), entry.EntrySize)
+	}
+}
+
+func PrintMapStats(stats []MapStats, profileName string) {
+	fmt.Println("Map stats (" + profileName + " rules, all skills)")
+	for _, mapStats := range stats {
+		mapFormat := "Doom"
+		if mapStats.IsHexenFormat {
+			mapFormat = "Hexen"
+		}
+
+		fmt.Printf("%s | %s format | %v things | %v linedefs | %v monsters health\n", mapStats.Name, mapFormat, mapStats.ThingCount, mapStats.LinedefCount, mapStats.MonsterHealth)
+
+		categories := make([]string, 0, len(mapStats.ThingsByCategory))
+		for category := range mapStats.ThingsByCategory {
+			categories = append(categories, category)
+		}
+		sort.Strings(categories)
+
+		for _, category := range categories {
+			fmt.Printf("  %s: %v\n", category, mapStats.ThingsByCategory[category])
+		}
+
+		if mapStats.UnknownThings > 0 {
+			fmt.Printf("  unknown: %v\n", mapStats.UnknownThings)
+		}
+
+		specials := make([]string, 0, len(mapStats.LinedefSpecials))
+		for special := range mapStats.LinedefSpecials {
+			specials = append(specials, special)
+		}
+		sort.Strings(specials)
+
+		for _, special := range specials {
+			fmt.Printf("  special %s: %v\n", special, mapStats.LinedefSpecials[special])
+		}
+	}
+}
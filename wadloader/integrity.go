@@ -0,0 +1,233 @@
+package wadloader
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type LumpIntegrityReport struct {
+	WADSize        int64
+	OutOfBounds    []LumpIntegrityEntry
+	SharedOffsets  []SharedLumpGroup
+	Overlaps       []LumpOverlap
+	Slack          []ByteRange
+	TotalSlackSize int64
+}
+
+type LumpIntegrityEntry struct {
+	Index int
+	Name  string
+	Lump  Lump
+}
+
+type SharedLumpGroup struct {
+	Offset uint32
+	Size   uint32
+	Lumps  []LumpIntegrityEntry
+}
+
+type LumpOverlap struct {
+	First        LumpIntegrityEntry
+	Second       LumpIntegrityEntry
+	OverlapStart int64
+	OverlapEnd   int64
+}
+
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+func (br ByteRange) Size() int64 {
+	return br.End - br.Start
+}
+
+func (wl *WADLoader) CheckLumpIntegrity() (LumpIntegrityReport, error) {
+	var report LumpIntegrityReport
+
+	if wl.WADReader == nil || len(wl.WADLumps) < 1 {
+		return report, errors.New("[Error] CheckLumpIntegrity: No WAD data or lumps loaded, cannot check integrity")
+	}
+
+	report.WADSize = wl.WADSize
+
+	var inBounds []LumpIntegrityEntry
+
+	for idx, lump := range wl.WADLumps {
+		entry := LumpIntegrityEntry{
+			Index: idx,
+			Name:  string(bytes.Trim(lump.LumpName[:], "\x00")),
+			Lump:  lump,
+		}
+
+		if !wl.IsLumpInBounds(lump) {
+			report.OutOfBounds = append(report.OutOfBounds, entry)
+			continue
+		}
+
+		// markers and empty lumps don't claim any data
+		if lump.LumpSize == 0 {
+			continue
+		}
+
+		inBounds = append(inBounds, entry)
+	}
+
+	sort.SliceStable(inBounds, func(i, j int) bool {
+		if inBounds[i].Lump.LumpOffset != inBounds[j].Lump.LumpOffset {
+			return inBounds[i].Lump.LumpOffset < inBounds[j].Lump.LumpOffset
+		}
+		return inBounds[i].Lump.LumpSize < inBounds[j].Lump.LumpSize
+	})
+
+	report.SharedOffsets = findSharedLumps(inBounds)
+	report.Overlaps = findOverlappingLumps(inBounds)
+	report.Slack = wl.findSlackRanges(inBounds)
+
+	for _, slack := range report.Slack {
+		report.TotalSlackSize += slack.Size()
+	}
+
+	return report, nil
+}
+
+func (wl *WADLoader) IsLumpInBounds(lump Lump) bool {
+	lumpEnd := int64(lump.LumpOffset) + int64(lump.LumpSize)
+	return lumpEnd <= wl.WADSize
+}
+
+// lumps must be sorted by offset
+func findSharedLumps(lumps []LumpIntegrityEntry) []SharedLumpGroup {
+	var groups []SharedLumpGroup
+
+	for i := 0; i < len(lumps); {
+		j := i + 1
+		for j < len(lumps) && lumps[j].Lump.LumpOffset == lumps[i].Lump.LumpOffset && lumps[j].Lump.LumpSize == lumps[i].Lump.LumpSize {
+			j++
+		}
+
+		if j-i > 1 {
+			groups = append(groups, SharedLumpGroup{
+				Offset: lumps[i].Lump.LumpOffset,
+				Size:   lumps[i].Lump.LumpSize,
+				Lumps:  append([]LumpIntegrityEntry{}, lumps[i:j]...),
+			})
+		}
+
+		i = j
+	}
+
+	return groups
+}
+
+// lumps must be sorted by offset, exact duplicates are reported as shared instead
+func findOverlappingLumps(lumps []LumpIntegrityEntry) []LumpOverlap {
+	var overlaps []LumpOverlap
+
+	for i := 0; i < len(lumps); i++ {
+		firstStart := int64(lumps[i].Lump.LumpOffset)
+		firstEnd := firstStart + int64(lumps[i].Lump.LumpSize)
+
+		for j := i + 1; j < len(lumps); j++ {
+			secondStart := int64(lumps[j].Lump.LumpOffset)
+			secondEnd := secondStart + int64(lumps[j].Lump.LumpSize)
+
+			if secondStart >= firstEnd {
+				break
+			}
+
+			if secondStart == firstStart && secondEnd == firstEnd {
+				continue
+			}
+
+			overlapEnd := firstEnd
+			if secondEnd < overlapEnd {
+				overlapEnd = secondEnd
+			}
+
+			overlaps = append(overlaps, LumpOverlap{
+				First:        lumps[i],
+				Second:       lumps[j],
+				OverlapStart: secondStart,
+				OverlapEnd:   overlapEnd,
+			})
+		}
+	}
+
+	return overlaps
+}
+
+// lumps must be sorted by offset
+func (wl *WADLoader) findSlackRanges(lumps []LumpIntegrityEntry) []ByteRange {
+	var used []ByteRange
+
+	// the header and the directory are referenced data too
+	used = append(used, ByteRange{Start: 0, End: 12})
+
+	dirStart := int64(wl.WADHeader.LumpDirectoryOffset)
+	dirEnd := dirStart + int64(wl.WADHeader.LumpEntries)*16
+	used = append(used, ByteRange{Start: dirStart, End: dirEnd})
+
+	for _, entry := range lumps {
+		start := int64(entry.Lump.LumpOffset)
+		used = append(used, ByteRange{Start: start, End: start + int64(entry.Lump.LumpSize)})
+	}
+
+	sort.Slice(used, func(i, j int) bool {
+		return used[i].Start < used[j].Start
+	})
+
+	var slack []ByteRange
+	var covered int64
+
+	for _, r := range used {
+		if r.Start > covered {
+			slack = append(slack, ByteRange{Start: covered, End: r.Start})
+		}
+		if r.End > covered {
+			covered = r.End
+		}
+	}
+
+	if covered < wl.WADSize {
+		slack = append(slack, ByteRange{Start: covered, End: wl.WADSize})
+	}
+
+	return slack
+}
+
+// shared by the console printer and the text file dumper
+func formatLumpIntegrity(report LumpIntegrityReport) []string {
+	var lines []string
+
+	lines = append(lines, fmt.Sprintf("Lump integrity report | WAD size: %v bytes", report.WADSize))
+
+	lines = append(lines, fmt.Sprintf("Out of bounds lumps: %v", len(report.OutOfBounds)))
+	for _, entry := range report.OutOfBounds {
+		lines = append(lines, fmt.Sprintf("  #%v %s | offset %v | size %v", entry.Index, entry.Name, entry.Lump.LumpOffset, entry.Lump.LumpSize))
+	}
+
+	lines = append(lines, fmt.Sprintf("Lumps sharing data: %v", len(report.SharedOffsets)))
+	for _, group := range report.SharedOffsets {
+		var names []string
+		for _, entry := range group.Lumps {
+			names = append(names, fmt.Sprintf("#%v %s", entry.Index, entry.Name))
+		}
+		lines = append(lines, fmt.Sprintf("  offset %v | size %v | %s", group.Offset, group.Size, strings.Join(names, ", ")))
+	}
+
+	lines = append(lines, fmt.Sprintf("Overlapping lumps: %v", len(report.Overlaps)))
+	for _, overlap := range report.Overlaps {
+		lines = append(lines, fmt.Sprintf("  #%v %s <-> #%v %s | bytes %v-%v", overlap.First.Index, overlap.First.Name, overlap.Second.Index, overlap.Second.Name, overlap.OverlapStart, overlap.OverlapEnd))
+	}
+
+	lines = append(lines, fmt.Sprintf("Unreferenced ranges (slack): %v | %v bytes", len(report.Slack), report.TotalSlackSize))
+	for _, slack := range report.Slack {
+		lines = append(lines, fmt.Sprintf("  bytes %v-%v | %v bytes", slack.Start, slack.End, slack.Size()))
+	}
+
+	return lines
+}
@@ -0,0 +1,230 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+)
+
+const (
+	RawScreenWidth  = 320
+	RawScreenHeight = 200
+)
+
+// raw 320x200 screens, used by Heretic and Hexen fullscreen graphics
+type RawScreen struct {
+	Name      string
+	PixelData []byte
+}
+
+// lumps that are never graphics even if their data happens to look like a patch
+var nonGraphicLumpNames = []string{
+	"PLAYPAL", "COLORMAP", "ENDOOM", "ENDTEXT", "ENDSTRF", "PNAMES", "TEXTURE1", "TEXTURE2",
+	"GENMIDI", "DMXGUS", "DMXGUSC", "SNDCURVE", "MAPINFO", "ZMAPINFO", "SNDINFO", "DEHACKED",
+	"DECORATE", "ANIMATED", "SWITCHES", "TINTTAB", "XLATAB", "AUTOPAGE", "LOADING",
+}
+
+// UI graphics (TITLEPIC, STBAR, M_*, WI*, STCFN*...) live outside of the
+// namespaces, so every other lump is checked for a valid patch header
+func (wl *WADLoader) DetectLooseGraphics() ([]Patch, []RawScreen, error) {
+	var graphics []Patch
+	var screens []RawScreen
+
+	if len(wl.WADLumps) < 1 {
+		return graphics, screens, errors.New("[Warn] DetectLooseGraphics: No Lumps loaded, cannot detect graphic lumps")
+	}
+
+	for idx, lump := range wl.looseLumps() {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+		if lump.LumpSize < 8 || isNonGraphicLumpName(lumpName) || !wl.IsLumpInBounds(lump) {
+			continue
+		}
+
+		lumpData, err := wl.ReadLumpData(lump)
+		if err != nil {
+			fmt.Println("[Warn] DetectLooseGraphics: Cannot read lump", idx, lumpName)
+			continue
+		}
+
+		if isPatchData(lumpData) {
+			patch, err := parsePatchData(lumpName, lumpData)
+			if err == nil {
+				graphics = append(graphics, patch)
+				continue
+			}
+		}
+
+		if len(lumpData) == RawScreenWidth*RawScreenHeight && isFullscreenGraphicName(lumpName) {
+			screens = append(screens, RawScreen{Name: lumpName, PixelData: lumpData})
+		}
+	}
+
+	return graphics, screens, nil
+}
+
+func (wl *WADLoader) LoadLooseGraphics() error {
+	graphics, screens, err := wl.DetectLooseGraphics()
+	if err != nil {
+		return err
+	}
+
+	wl.Graphics = graphics
+	wl.Screens = screens
+
+	return nil
+}
+
+// lumps outside of every X_START/X_END namespace and of the maps
+func (wl *WADLoader) looseLumps() []Lump {
+	var lumps []Lump
+	namespaceDepth := 0
+
+	for idx := 0; idx < len(wl.WADLumps); idx++ {
+		lump := wl.WADLumps[idx]
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+		if strings.HasSuffix(lumpName, "_START") && lump.LumpSize == 0 {
+			namespaceDepth++
+			continue
+		}
+
+		if strings.HasSuffix(lumpName, "_END") && lump.LumpSize == 0 {
+			if namespaceDepth > 0 {
+				namespaceDepth--
+			}
+			continue
+		}
+
+		if namespaceDepth > 0 {
+			continue
+		}
+
+		// a map header is followed by its data lumps
+		if isMapHeader(wl.WADLumps, idx) {
+			for idx+1 < len(wl.WADLumps) && isMapDataLumpName(string(bytes.Trim(wl.WADLumps[idx+1].LumpName[:], "\x00"))) {
+				idx++
+			}
+			continue
+		}
+
+		lumps = append(lumps, lump)
+	}
+
+	return lumps
+}
+
+func isNonGraphicLumpName(lumpName string) bool {
+	for _, name := range nonGraphicLumpNames {
+		if name == lumpName {
+			return true
+		}
+	}
+
+	// demos, music and sounds
+	return strings.HasPrefix(lumpName, "DEMO") || strings.HasPrefix(lumpName, "D_") ||
+		strings.HasPrefix(lumpName, "DS") || strings.HasPrefix(lumpName, "DP") || strings.HasPrefix(lumpName, "MUS_")
+}
+
+func isFullscreenGraphicName(lumpName string) bool {
+	for _, profile := range []GameProfile{DoomProfile, HereticProfile, HexenProfile, StrifeProfile, ChexProfile} {
+		for _, name := range profile.FullscreenGraphics {
+			if name == lumpName {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// the header must agree with the lump size: sane dimensions, column offsets
+// after the offsets table and inside the lump, posts ending inside the lump
+// and, for non tall patches, inside the patch height
+func isPatchData(data []byte) bool {
+	if len(data) < 8 {
+		return false
+	}
+
+	width := int(binary.LittleEndian.Uint16(data[0:]))
+	height := int(binary.LittleEndian.Uint16(data[2:]))
+
+	if width < 1 || width > 4096 || height < 1 || height > 4096 {
+		return false
+	}
+
+	columnsStart := 8 + width*4
+	if columnsStart > len(data) {
+		return false
+	}
+
+	for column := 0; column < width; column++ {
+		columnOffset := int(binary.LittleEndian.Uint32(data[8+column*4:]))
+		if columnOffset < columnsStart || columnOffset >= len(data) {
+			return false
+		}
+
+		if !isPatchColumn(data, columnOffset, height) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// posts are topdelta, length, unused byte, pixels and another unused byte, 0xFF ends the column
+func isPatchColumn(data []byte, offset int, height int) bool {
+	for {
+		if offset >= len(data) {
+			return false
+		}
+
+		topDelta := int(data[offset])
+		if topDelta == 0xFF {
+			return true
+		}
+
+		if offset+1 >= len(data) {
+			return false
+		}
+
+		length := int(data[offset+1])
+		if height <= 254 && topDelta+length > height {
+			return false
+		}
+
+		offset += length + 4
+	}
+}
+
+func RawScreenToImage(screen RawScreen, palette Palette) *image.RGBA {
+	screenImg := image.NewRGBA(image.Rect(0, 0, RawScreenWidth, RawScreenHeight))
+
+	for y := 0; y < RawScreenHeight; y++ {
+		for x := 0; x < RawScreenWidth; x++ {
+			pixelColor := palette[screen.PixelData[x+y*RawScreenWidth]]
+			screenImg.Set(x, y, color.RGBA{pixelColor.Red, pixelColor.Green, pixelColor.Blue, 255})
+		}
+	}
+
+	return screenImg
+}
+
+func ExportRawScreen(screen RawScreen, palette Palette, outputFolder string) error {
+	screenImg := RawScreenToImage(screen, palette)
+
+	screenFile, err := os.Create(outputFolder + "/" + screen.Name + ".png")
+	if err != nil {
+		return errors.New("[Error] ExportRawScreen: Cannot create the target file for the screen - " + screen.Name + " - " + err.Error())
+	}
+
+	defer screenFile.Close()
+	png.Encode(screenFile, screenImg)
+
+	return nil
+}
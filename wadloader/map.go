@@ -4,7 +4,6 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
-	"io"
 )
 
 var MapLumpsNames = []string{"THINGS", "LINEDEFS", "SIDEDEFS", "VERTEXES", "SEGS", "SSECTORS", "NODES", "SECTORS", "REJECT", "BLOCKMAP"}
@@ -21,6 +20,10 @@ type Map struct {
 	Nodes []Node
 	Sectors []Sector
 
+	// Hexen format maps keep the full things and linedefs here, Things and Linedefs hold the common fields
+	IsHexenFormat bool
+	HexenThings []HexenThing
+	HexenLinedefs []HexenLinedef
 }
 
 type Vertex struct {
@@ -55,6 +58,28 @@ type Thing struct {
 	Flags uint16
 }
 
+type HexenThing struct {
+	TID uint16
+	XPos int16
+	YPos int16
+	Height int16
+	Angle uint16
+	Type uint16
+	Flags uint16
+	Special uint8
+	Args [5]uint8
+}
+
+type HexenLinedef struct {
+	StartVertex uint16
+	EndVertex uint16
+	Flags uint16
+	Special uint8
+	Args [5]uint8
+	RightSidedef uint16
+	LeftSidedef uint16
+}
+
 type Node struct {
 	XPartition int16
 	YPartition int16
@@ -108,196 +133,73 @@ type Blockmap struct { //TODO: Broken struct
 }
 
 // Map lump parsing
-func (wp *WADParser) parseMapThings(lump Lump) []Thing {  // TODO: can we do this generic for all map lumps???
-	wp.checkValidByteReader()
-
-	var readThings []Thing
-	lumpThingsCount := int(lump.LumpSize / 10)
-
-	for i := 0; i < lumpThingsCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 10))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
-
-		var t Thing
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &t)
-
-		if err != nil {
-			fmt.Println("[Warn] parseMapThings: Error while reading a THING (skipping): " + err.Error())
-			continue
-		}
-
-		readThings = append(readThings, t)
-	}
-
-	return readThings
+func (wp *WADParser) parseMapThings(lump Lump) []Thing {
+	return parseMapLumpEntries[Thing](wp, lump, 10, "parseMapThings", "THING")
 }
 
 func (wp *WADParser) parseMapLinedefs(lump Lump) []Linedef {
-	wp.checkValidByteReader()
-
-	var readLinedef []Linedef
-	lumpThingsCount := int(lump.LumpSize / 14)
-
-	for i := 0; i < lumpThingsCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 14))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
-
-		var l Linedef
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &l)
-
-		if err != nil {
-			fmt.Println("[Warn] parseMapLinedefs: Error while reading a LINEDEF (skipping): " + err.Error())
-			continue
-		}
+	return parseMapLumpEntries[Linedef](wp, lump, 14, "parseMapLinedefs", "LINEDEF")
+}
 
-		readLinedef = append(readLinedef, l)
-	}
+func (wp *WADParser) parseMapHexenThings(lump Lump) []HexenThing {
+	return parseMapLumpEntries[HexenThing](wp, lump, 20, "parseMapHexenThings", "THING")
+}
 
-	return readLinedef
+func (wp *WADParser) parseMapHexenLinedefs(lump Lump) []HexenLinedef {
+	return parseMapLumpEntries[HexenLinedef](wp, lump, 16, "parseMapHexenLinedefs", "LINEDEF")
 }
 
 func (wp *WADParser) parseMapSidedefs(lump Lump) []Sidedef {
-	wp.checkValidByteReader()
-
-	var readSidedef []Sidedef
-	lumpSidedefCount := int(lump.LumpSize / 30)
-
-	for i := 0; i < lumpSidedefCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 30))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
-
-		var s Sidedef
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &s)
-
-		if err != nil {
-			fmt.Println("[Warn] parseMapSidedefs: Error while reading a SIDEDEF (skipping): " + err.Error())
-			continue
-		}
-
-		readSidedef = append(readSidedef, s)
-	}
-
-	return readSidedef
+	return parseMapLumpEntries[Sidedef](wp, lump, 30, "parseMapSidedefs", "SIDEDEF")
 }
 
 func (wp *WADParser) parseMapVertexes(lump Lump) []Vertex {
-	wp.checkValidByteReader()
-
-	var readVertex []Vertex
-	lumpVertexCount := int(lump.LumpSize / 4)
-
-	for i := 0; i < lumpVertexCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 4))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
-
-		var v Vertex
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &v)
-
-		if err != nil {
-			fmt.Println("[Warn] parseMapVertexes: Error while reading a VERTEX (skipping): " + err.Error())
-			continue
-		}
-
-		readVertex = append(readVertex, v)
-	}
-
-	return readVertex
+	return parseMapLumpEntries[Vertex](wp, lump, 4, "parseMapVertexes", "VERTEX")
 }
 
 func (wp *WADParser) parseMapSegs(lump Lump) []Seg {
-	wp.checkValidByteReader()
-
-	var readSeg []Seg
-	lumpSegCount := int(lump.LumpSize / 12)
-
-	for i := 0; i < lumpSegCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 12))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
-
-		var s Seg
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &s)
-
-		if err != nil {
-			fmt.Println("[Warn] parseMapSegs: Error while reading a SEG (skipping): " + err.Error())
-			continue
-		}
-
-		readSeg = append(readSeg, s)
-	}
-
-	return readSeg
+	return parseMapLumpEntries[Seg](wp, lump, 12, "parseMapSegs", "SEG")
 }
 
 func (wp *WADParser) parseMapSSectors(lump Lump) []SSector {
-	wp.checkValidByteReader()
-
-	var readSSector []SSector
-	lumpSSectorCount := int(lump.LumpSize / 4)
-
-	for i := 0; i < lumpSSectorCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 4))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
-
-		var ss SSector
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &ss)
-
-		if err != nil {
-			fmt.Println("[Warn] parseMapSSectors: Error while reading a SSECTOR (skipping): " + err.Error())
-			continue
-		}
-
-		readSSector = append(readSSector, ss)
-	}
-
-	return readSSector
+	return parseMapLumpEntries[SSector](wp, lump, 4, "parseMapSSectors", "SSECTOR")
 }
 
 func (wp *WADParser) parseMapNodes(lump Lump) []Node {
-	wp.checkValidByteReader()
-
-	var readNode []Node
-	lumpNodeCount := int(lump.LumpSize / 28)
+	return parseMapLumpEntries[Node](wp, lump, 28, "parseMapNodes", "NODE")
+}
 
-	for i := 0; i < lumpNodeCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 28))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
+func (wp *WADParser) parseMapSectors(lump Lump) []Sector {
+	return parseMapLumpEntries[Sector](wp, lump, 26, "parseMapSectors", "SECTOR")
+}
 
-		var n Node
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &n)
+// map lumps are arrays of fixed size entries, broken entries are skipped
+func parseMapLumpEntries[T any](wp *WADParser, lump Lump, entrySize int, funcName string, entryName string) []T {
+	var entries []T
 
-		if err != nil {
-			fmt.Println("[Warn] parseMapNodes: Error while reading a NODE (skipping): " + err.Error())
-			continue
-		}
-
-		readNode = append(readNode, n)
+	lumpData, err := wp.readLump(lump)
+	if err != nil {
+		fmt.Println("[Warn] " + funcName + ": Error while reading the " + entryName + " lump (skipping): " + err.Error())
+		return entries
 	}
 
-	return readNode
-}
-
-func (wp *WADParser) parseMapSectors(lump Lump) []Sector {
-	wp.checkValidByteReader()
-
-	var readSector []Sector
-	lumpSectorCount := int(lump.LumpSize / 26)
+	entryCount := len(lumpData) / entrySize
 
-	for i := 0; i < lumpSectorCount; i++ {
-		byteOffset := int64(lump.LumpOffset) + int64((i * 26))
-		wp.byteReader.Seek(int64(byteOffset), io.SeekStart)
+	for i := 0; i < entryCount; i++ {
+		entryData := lumpData[i*entrySize : (i+1)*entrySize]
 
-		var s Sector
-		err := binary.Read(wp.byteReader, binary.LittleEndian, &s)
+		var entry T
+		err := binary.Read(bytes.NewReader(entryData), binary.LittleEndian, &entry)
 
 		if err != nil {
-			fmt.Println("[Warn] parseMapSectors: Error while reading a SECTOR (skipping): " + err.Error())
+			fmt.Println("[Warn] " + funcName + ": Error while reading a " + entryName + " (skipping): " + err.Error())
 			continue
 		}
 
-		readSector = append(readSector, s)
+		entries = append(entries, entry)
 	}
 
-	return readSector
+	return entries
 }
 
 // helper functions
@@ -310,3 +212,34 @@ func IsLumpAMapLump(l *Lump) bool {
 	}
 	return false
 }
+
+func hexenThingsToThings(hexenThings []HexenThing) []Thing {
+	things := make([]Thing, len(hexenThings))
+	for idx, hexenThing := range hexenThings {
+		things[idx] = Thing{
+			XPos:  hexenThing.XPos,
+			YPos:  hexenThing.YPos,
+			Angle: hexenThing.Angle,
+			Type:  hexenThing.Type,
+			Flags: hexenThing.Flags,
+		}
+	}
+	return things
+}
+
+// the special goes into LineType and its first argument (usually the sector tag) into SectorTag
+func hexenLinedefsToLinedefs(hexenLinedefs []HexenLinedef) []Linedef {
+	linedefs := make([]Linedef, len(hexenLinedefs))
+	for idx, hexenLinedef := range hexenLinedefs {
+		linedefs[idx] = Linedef{
+			StartVertex:  hexenLinedef.StartVertex,
+			EndVertex:    hexenLinedef.EndVertex,
+			Flags:        hexenLinedef.Flags,
+			LineType:     uint16(hexenLinedef.Special),
+			SectorTag:    uint16(hexenLinedef.Args[0]),
+			RightSidedef: hexenLinedef.RightSidedef,
+			LeftSidedef:  hexenLinedef.LeftSidedef,
+		}
+	}
+	return linedefs
+}
@@ -0,0 +1,47 @@
+package wadloader
+
+// things are counted for every skill level, linedefs without special aren't counted
+type MapStats struct {
+	Name             string
+	IsHexenFormat    bool
+	ThingCount       int
+	ThingsByCategory map[string]int
+	UnknownThings    int
+	MonsterHealth    int
+	LinedefCount     int
+	LinedefSpecials  map[string]int
+}
+
+func (wl *WADLoader) GetMapStats(m Map) MapStats {
+	profile := wl.profile()
+
+	stats := MapStats{
+		Name:             m.Name,
+		IsHexenFormat:    m.IsHexenFormat,
+		ThingCount:       len(m.Things),
+		ThingsByCategory: make(map[string]int),
+		LinedefCount:     len(m.Linedefs),
+		LinedefSpecials:  make(map[string]int),
+	}
+
+	for _, thing := range m.Things {
+		info, found := profile.ThingInfo(thing.Type)
+		if !found {
+			stats.UnknownThings++
+			continue
+		}
+
+		stats.ThingsByCategory[info.Category]++
+		stats.MonsterHealth += info.Health
+	}
+
+	for _, linedef := range m.Linedefs {
+		if linedef.LineType == 0 {
+			continue
+		}
+
+		stats.LinedefSpecials[profile.LinedefTypeName(linedef.LineType)]++
+	}
+
+	return stats
+}
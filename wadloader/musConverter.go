@@ -0,0 +1,200 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	musMaxScoreLength = 0xFFFF
+	// MUS channels 0-14 are melodic, 15 is percussion
+	musMelodicChannelCount = 15
+	// percussion instruments are listed as their drum note + 100
+	musPercussionInstrumentBase = 100
+)
+
+type musScoreEvent struct {
+	tick int
+	data []byte
+}
+
+// MUS controller number of a MIDI controller, system events included
+func midiToMUSController(controller int) (int, bool) {
+	for musController := 1; musController < len(musToMIDIControllers); musController++ {
+		if musToMIDIControllers[musController] == controller {
+			return musController, true
+		}
+	}
+	return 0, false
+}
+
+// MUS delays are 7 bits groups, most significant first, with the high bit set on all but the last
+func appendMUSDelay(data []byte, delay int) []byte {
+	groups := []byte{byte(delay & 0x7F)}
+	for delay >>= 7; delay > 0; delay >>= 7 {
+		groups = append([]byte{byte(delay&0x7F) | 0x80}, groups...)
+	}
+	return append(data, groups...)
+}
+
+// converts a Standard MIDI File to MUS, timing goes through the tempo map into 140 Hz ticks.
+// The report lists what the MUS version loses
+func ConvertMIDIToMUS(data []byte) ([]byte, []string, error) {
+	song, err := ParseMIDI(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := append([]string(nil), song.Problems...)
+
+	var channelMap [16]int
+	var channelPrograms [16]int
+	var lastVolumes [16]int
+	for idx := range channelMap {
+		channelMap[idx], lastVolumes[idx] = -1, -1
+	}
+
+	melodicChannels := 0
+	droppedChannels := make(map[int]bool)
+	droppedControllers := make(map[int]int)
+	instruments := make(map[int]bool)
+	noteStarts := make(map[[2]int]int)
+	shortNotes := 0
+
+	var events []musScoreEvent
+	endTick := 0
+
+	for _, event := range song.Events {
+		tick := int(math.Round(event.Time * musTickRate))
+		if tick > endTick {
+			endTick = tick
+		}
+
+		if event.Kind == SongEnd || event.Kind == SongTempo {
+			continue
+		}
+
+		channel := event.Channel & 0x0F
+		musChannel := musPercussionChannel
+
+		if channel != MIDIPercussionChannel {
+			if channelMap[channel] < 0 {
+				if melodicChannels >= musMelodicChannelCount {
+					droppedChannels[channel] = true
+					continue
+				}
+				channelMap[channel] = melodicChannels
+				melodicChannels++
+			}
+			musChannel = channelMap[channel]
+		}
+
+		var eventData []byte
+
+		switch event.Kind {
+		case SongNoteOff:
+			eventData = []byte{musReleaseNote<<4 | byte(musChannel), byte(event.Data1)}
+
+			if start, found := noteStarts[[2]int{channel, event.Data1}]; found && start == tick {
+				shortNotes++
+			}
+			delete(noteStarts, [2]int{channel, event.Data1})
+		case SongNoteOn:
+			// the volume is only sent when it changes
+			if event.Data2 != lastVolumes[musChannel] {
+				eventData = []byte{musPlayNote<<4 | byte(musChannel), byte(event.Data1) | 0x80, byte(event.Data2)}
+				lastVolumes[musChannel] = event.Data2
+			} else {
+				eventData = []byte{musPlayNote<<4 | byte(musChannel), byte(event.Data1)}
+			}
+
+			if channel == MIDIPercussionChannel {
+				instruments[event.Data1+musPercussionInstrumentBase] = true
+			} else {
+				instruments[channelPrograms[channel]] = true
+			}
+			noteStarts[[2]int{channel, event.Data1}] = tick
+		case SongPitchBend:
+			eventData = []byte{musPitchBend<<4 | byte(musChannel), byte(event.Data1 >> 6)}
+		case SongProgram:
+			eventData = []byte{musController<<4 | byte(musChannel), musControllerProgram, byte(event.Data1)}
+			channelPrograms[channel] = event.Data1
+		case SongController:
+			controller, found := midiToMUSController(event.Data1)
+			if !found {
+				droppedControllers[event.Data1]++
+				continue
+			}
+
+			if controller < musFirstSystemController {
+				eventData = []byte{musController<<4 | byte(musChannel), byte(controller), byte(event.Data2)}
+			} else {
+				eventData = []byte{musSystemEvent<<4 | byte(musChannel), byte(controller)}
+			}
+		}
+
+		events = append(events, musScoreEvent{tick: tick, data: eventData})
+	}
+
+	// the last event of each tick carries the delay to the next one
+	var score []byte
+	for idx, event := range events {
+		nextTick := endTick
+		if idx+1 < len(events) {
+			nextTick = events[idx+1].tick
+		}
+
+		eventData := append([]byte(nil), event.data...)
+		if nextTick > event.tick {
+			eventData[0] |= 0x80
+			eventData = appendMUSDelay(eventData, nextTick-event.tick)
+		}
+		score = append(score, eventData...)
+	}
+	score = append(score, musScoreEnd<<4)
+
+	if len(score) > musMaxScoreLength {
+		return nil, report, fmt.Errorf("[Error] ConvertMIDIToMUS: The score takes %d bytes, MUS holds up to %d", len(score), musMaxScoreLength)
+	}
+
+	for _, channel := range sortedKeys(droppedChannels) {
+		report = append(report, fmt.Sprintf("MIDI channel %d dropped, MUS has %d melodic channels", channel, musMelodicChannelCount))
+	}
+	for _, controller := range sortedControllers(droppedControllers) {
+		report = append(report, fmt.Sprintf("controller %d dropped %d times, MUS cannot carry it", controller, droppedControllers[controller]))
+	}
+	if shortNotes > 0 {
+		report = append(report, fmt.Sprintf("%d notes are shorter than a 140 Hz tick and end as they start", shortNotes))
+	}
+
+	instrumentList := sortedKeys(instruments)
+
+	header := musHeader{
+		ID:              [4]byte{'M', 'U', 'S', 0x1A},
+		ScoreLength:     uint16(len(score)),
+		ScoreStart:      uint16(16 + 2*len(instrumentList)),
+		PrimaryChannels: uint16(melodicChannels),
+		InstrumentCount: uint16(len(instrumentList)),
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	for _, instrument := range instrumentList {
+		binary.Write(&buf, binary.LittleEndian, uint16(instrument))
+	}
+	buf.Write(score)
+
+	return buf.Bytes(), report, nil
+}
+
+func sortedControllers(counts map[int]int) []int {
+	controllers := make([]int, 0, len(counts))
+	for controller := range counts {
+		controllers = append(controllers, controller)
+	}
+	sort.Ints(controllers)
+	return controllers
+}
@@ -3,43 +3,157 @@ package wadloader
 import (
 	"encoding/binary"
 	"errors"
-	"io"
 	"os"
 	"strings"
 )
 
-func (wp *WADParser) getMusicFormatFromLump(lump *Lump) (string, error) {
-	wp.checkValidByteReader()
+// MOD signatures sit after the 31 sample headers, at offset 1080
+const musicHeaderSize = 1084
 
-	wp.byteReader.Seek(int64(lump.LumpOffset), io.SeekStart)
+var modSignatures = []string{"M.K.", "M!K!", "M&K!", "FLT4", "FLT8", "4CHN", "6CHN", "8CHN", "CD81", "OKTA", "OCTA"}
 
-	// Look for identification header
-	// ASCII = MTHD -> MIDI format
-	// ASCII = MUS -> MUS format
+func (wp *WADParser) getMusicFormatFromLump(lump *Lump) (string, error) {
+	headerSize := musicHeaderSize
+	if int(lump.LumpSize) < headerSize {
+		headerSize = int(lump.LumpSize)
+	}
 
-	var header [4]byte
-	err := binary.Read(wp.byteReader, binary.LittleEndian, &header)
+	header, err := wp.readAt(int64(lump.LumpOffset), headerSize)
 
 	if err != nil {
 		return "Unknown", errors.New("[Error] getMusicFormatFromLump: Couldn't read music lump header")
 	}
 
-	musicFormat := string(header[:])
+	musicFormat := detectMusicFormat(header, int(lump.LumpSize))
+	if musicFormat == "" {
+		return "Invalid", errors.New("[Error] getMusicFormatFromLump: Invalid music format detected")
+	}
 
-	if strings.Contains(musicFormat, "MThd") {
-		return "MIDI", nil
-	} else if strings.Contains(musicFormat, "MUS") {
-		return "MUS", nil
+	return musicFormat, nil
+}
+
+// identifies the format by its signature, header holds the first bytes of a size bytes lump
+func detectMusicFormat(header []byte, size int) string {
+	hasAt := func(offset int, signature string) bool {
+		return len(header) >= offset+len(signature) && string(header[offset:offset+len(signature)]) == signature
 	}
 
-	return "Invalid", errors.New("[Error] getMusicFormatFromLump: Invalid music format detected")
+	switch {
+	case hasAt(0, "MThd"):
+		return "MIDI"
+	case hasAt(0, "MUS\x1a"):
+		return "MUS"
+	case hasAt(0, "RIFF") && hasAt(8, "RMID"):
+		return "RMI"
+	case hasAt(0, "RIFF") && hasAt(8, "WAVE"):
+		return "WAV"
+	case hasAt(0, "OggS"):
+		return "OGG"
+	case hasAt(0, "fLaC"):
+		return "FLAC"
+	case hasAt(0, "ID3") || isMP3Frame(header):
+		return "MP3"
+	case hasAt(0, "Extended Module: "):
+		return "XM"
+	case hasAt(0, "IMPM"):
+		return "IT"
+	case hasAt(44, "SCRM"):
+		return "S3M"
+	case hasAt(0, "DBRAWOPL"):
+		return "DRO"
+	case hasAt(0, "RAWADATA"):
+		return "RAW"
+	case isMODHeader(header):
+		return "MOD"
+	case isIMFData(header, size):
+		return "IMF"
+	}
+
+	return ""
 }
 
-func (wp *WADParser) ExportSong(song *MusicLump, outputFolder string) error {
-	wp.checkValidByteReader()
-	wp.byteReader.Seek(int64(song.lump.LumpOffset), io.SeekStart)
+// an MPEG audio frame sync without ID3 tag: 11 set bits, a valid version, layer and bitrate
+func isMP3Frame(header []byte) bool {
+	if len(header) < 4 || header[0] != 0xFF || header[1]&0xE0 != 0xE0 {
+		return false
+	}
+
+	version := (header[1] >> 3) & 0x03
+	layer := (header[1] >> 1) & 0x03
+	bitrate := header[2] >> 4
+	sampleRate := (header[2] >> 2) & 0x03
+
+	return version != 1 && layer != 0 && bitrate != 0x0F && sampleRate != 0x03
+}
+
+func isMODHeader(header []byte) bool {
+	if len(header) < musicHeaderSize {
+		return false
+	}
+
+	signature := string(header[1080:1084])
+	for _, modSignature := range modSignatures {
+		if signature == modSignature {
+			return true
+		}
+	}
+
+	// "10CH" to "32CH"
+	return signature[0] >= '0' && signature[0] <= '9' && signature[1] >= '0' && signature[1] <= '9' && signature[2:] == "CH"
+}
+
+// OPL registers an IMF song writes to
+func isOPLRegister(register byte) bool {
+	switch {
+	case register <= 0x08, register == 0xBD:
+		return true
+	case register >= 0x20 && register <= 0x95:
+		return register&0x1F <= 0x15
+	case register >= 0xA0 && register <= 0xC8:
+		return register&0x0F <= 0x08
+	case register >= 0xE0 && register <= 0xF5:
+		return true
+	}
+	return false
+}
+
+// IMF has no signature: type 1 files start with the data length, type 0 files are bare
+// 4 byte register, value and delay records opening with an empty one. Every record read
+// must write an OPL register, and not only the register 0 of the empty records
+func isIMFData(header []byte, size int) bool {
+	if size < 4 || len(header) < 4 {
+		return false
+	}
 
-	filename := song.name + "." + song.format
+	records := header
+	dataLength := int(binary.LittleEndian.Uint16(header))
+
+	if dataLength > 0 && dataLength%4 == 0 && dataLength <= size-2 {
+		records = header[2:]
+		if dataLength < len(records) {
+			records = records[:dataLength]
+		}
+	} else if size%4 != 0 || binary.LittleEndian.Uint32(header) != 0 {
+		return false
+	}
+
+	if len(records) < 4 {
+		return false
+	}
+
+	writesRegisters := false
+	for pos := 0; pos+4 <= len(records); pos += 4 {
+		if !isOPLRegister(records[pos]) {
+			return false
+		}
+		writesRegisters = writesRegisters || records[pos] != 0
+	}
+
+	return writesRegisters
+}
+
+func (wp *WADParser) ExportSong(song *MusicLump, outputFolder string) error {
+	filename := song.name + "." + musicFormatExtension(song.format)
 	finalPath := outputFolder + "/" + filename
 
 	os.Remove(finalPath)
@@ -51,8 +165,7 @@ func (wp *WADParser) ExportSong(song *MusicLump, outputFolder string) error {
 
 	defer file.Close()
 
-	lumpData := make([]byte, song.lump.LumpSize)
-	errRead := binary.Read(wp.byteReader, binary.LittleEndian, &lumpData)
+	lumpData, errRead := wp.readLump(song.lump)
 
 	if errRead != nil {
 		return errors.New("[Error] ExportSong: Cannot read the song lump data")
@@ -64,34 +177,20 @@ func (wp *WADParser) ExportSong(song *MusicLump, outputFolder string) error {
 }
 
 func (wl *WADLoader) ExportAllSongs(folderName string) error {
-	wl.WADParser.checkValidByteReader()
+	wl.WADParser.checkValidReader()
 
 	if len(wl.Music) < 1 {
 		return errors.New("[Error] ExportAllSongs: No music data inside WAD Loader")
 	}
 
-	if folderName == "" {
-		return errors.New("[Error] ExportAllSongs: No folder name specified")
-	}
-
-	lastChar := folderName[len(folderName)-1:]
-	if lastChar == "/" {
-		folderName = folderName[:len(folderName)-1]
-	}
-
-	_, err := os.Stat(folderName)
+	folderName, err := createFolder(folderName)
 	if err != nil {
-		err = os.Mkdir(folderName, 0755)
-		if err != nil {
-			return errors.New("[Error] ExportAllSongs: Cannot create the target folder")
-		}
-	}
-
-	for _, song := range wl.Music {
-		wl.WADParser.ExportSong(&song, folderName)
+		return errors.New("[Error] ExportAllSongs: Cannot create the target folder - " + err.Error())
 	}
 
-	return nil
+	return runWorkers(len(wl.Music), wl.workerCount(), func(idx int) error {
+		return wl.WADParser.ExportSong(&wl.Music[idx], folderName)
+	})
 }
 
 func (wl *WADLoader) GetMusicLumpFromSongName(songName string) (*MusicLump, error) {
@@ -110,3 +209,15 @@ func (wl *WADLoader) GetMusicLumpFromSongName(songName string) (*MusicLump, erro
 
 	return &musicLump, errors.New("[Error] GetMusicLumpFromSongName: Song name not found")
 }
+
+// file extension used when the song is written outside of a WAD
+func musicFormatExtension(format string) string {
+	switch format {
+	case "MIDI":
+		return "mid"
+	case "MUS", "RMI", "WAV", "OGG", "FLAC", "MP3", "XM", "IT", "S3M", "DRO", "RAW", "MOD", "IMF":
+		return strings.ToLower(format)
+	}
+
+	return "lmp"
+}
@@ -0,0 +1,447 @@
+package wadloader
+
+import (
+	"math"
+)
+
+// OPL2/OPL3 FM synthesis with 2 operator channels. It works on the register
+// interface of the chip (fnum/block, ADSR rates, levels, waveforms, feedback)
+// and renders at any sample rate instead of emulating the chip cycles
+const (
+	// fnum/block frequencies are relative to the chip sample rate
+	oplChipRate = 49716
+
+	oplSineSize = 1024
+	// attenuation (dB) where an operator is considered silent
+	oplSilence = 96.0
+	// a full scale operator output moves the carrier phase by 4 cycles
+	oplModulationDepth = 4.0
+	// output of a full scale channel, as the 13 bits chip output
+	oplChannelScale = 4096.0
+)
+
+type oplEnvelopeStage int
+
+const (
+	oplStageOff oplEnvelopeStage = iota
+	oplStageAttack
+	oplStageDecay
+	oplStageSustain
+	oplStageRelease
+)
+
+var oplSineTable = func() [oplSineSize]float64 {
+	var table [oplSineSize]float64
+	for i := range table {
+		table[i] = math.Sin(2 * math.Pi * float64(i) / oplSineSize)
+	}
+	return table
+}()
+
+// the MULT register values, 0 halves the frequency
+var oplMultipliers = [16]float64{0.5, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 10, 12, 12, 15, 15}
+
+// key scale level attenuation of the top fnum bits, in 0.75 dB steps for 6 dB/octave
+var oplKeyScaleLevels = [16]float64{0, 32, 40, 45, 48, 51, 53, 55, 56, 58, 59, 60, 61, 62, 63, 64}
+
+// the register offset of the modulator of each channel, the carrier is 3 operators later
+var oplChannelOperators = [9]int{0x00, 0x01, 0x02, 0x08, 0x09, 0x0A, 0x10, 0x11, 0x12}
+
+type oplOperator struct {
+	tremolo       bool
+	vibrato       bool
+	sustained     bool
+	keyScaleRate  bool
+	multiplier    float64
+	keyScaleLevel uint8
+	totalLevel    uint8
+	attackRate    uint8
+	decayRate     uint8
+	sustainLevel  uint8
+	releaseRate   uint8
+	waveform      uint8
+
+	phase    float64
+	envelope float64
+	stage    oplEnvelopeStage
+	// last outputs, the modulator feeds back their average
+	outputs [2]float64
+}
+
+type oplChannel struct {
+	fnum     uint16
+	block    uint8
+	keyOn    bool
+	feedback uint8
+	additive bool
+	left     bool
+	right    bool
+
+	modulator *oplOperator
+	carrier   *oplOperator
+}
+
+type OPL struct {
+	sampleRate     int
+	opl3           bool
+	waveformSelect bool
+	deepTremolo    bool
+	deepVibrato    bool
+
+	// operators are indexed by register offset, 0x20 per register bank
+	operators [0x40]oplOperator
+	channels  [18]oplChannel
+
+	tremoloPhase float64
+	vibratoPhase float64
+}
+
+func NewOPL(sampleRate int) *OPL {
+	opl := &OPL{sampleRate: sampleRate}
+
+	for idx := range opl.channels {
+		bank := (idx / 9) * 0x20
+		channel := &opl.channels[idx]
+		channel.modulator = &opl.operators[bank+oplChannelOperators[idx%9]]
+		channel.carrier = &opl.operators[bank+oplChannelOperators[idx%9]+3]
+		channel.left, channel.right = true, true
+	}
+
+	for idx := range opl.operators {
+		opl.operators[idx].envelope = oplSilence
+		opl.operators[idx].multiplier = oplMultipliers[0]
+	}
+
+	return opl
+}
+
+// channels 9-17 only exist once OPL3 mode is enabled
+func (opl *OPL) ChannelCount() int {
+	if opl.opl3 {
+		return 18
+	}
+	return 9
+}
+
+// registers 0x100-0x1FF are the second OPL3 bank
+func (opl *OPL) WriteRegister(register uint16, value uint8) {
+	bank := int(register >> 8)
+	reg := int(register & 0xFF)
+
+	switch {
+	case register == 0x01:
+		opl.waveformSelect = value&0x20 != 0
+	case register == 0x105:
+		opl.opl3 = value&0x01 != 0
+	case reg == 0xBD && bank == 0:
+		opl.deepTremolo = value&0x80 != 0
+		opl.deepVibrato = value&0x40 != 0
+	case reg >= 0x20 && reg <= 0x35:
+		op := opl.operator(bank, reg-0x20)
+		if op == nil {
+			return
+		}
+		op.tremolo = value&0x80 != 0
+		op.vibrato = value&0x40 != 0
+		op.sustained = value&0x20 != 0
+		op.keyScaleRate = value&0x10 != 0
+		op.multiplier = oplMultipliers[value&0x0F]
+	case reg >= 0x40 && reg <= 0x55:
+		op := opl.operator(bank, reg-0x40)
+		if op == nil {
+			return
+		}
+		op.keyScaleLevel = value >> 6
+		op.totalLevel = value & 0x3F
+	case reg >= 0x60 && reg <= 0x75:
+		op := opl.operator(bank, reg-0x60)
+		if op == nil {
+			return
+		}
+		op.attackRate = value >> 4
+		op.decayRate = value & 0x0F
+	case reg >= 0x80 && reg <= 0x95:
+		op := opl.operator(bank, reg-0x80)
+		if op == nil {
+			return
+		}
+		op.sustainLevel = value >> 4
+		op.releaseRate = value & 0x0F
+	case reg >= 0xE0 && reg <= 0xF5:
+		op := opl.operator(bank, reg-0xE0)
+		if op == nil {
+			return
+		}
+		op.waveform = value & 0x07
+	case reg >= 0xA0 && reg <= 0xA8:
+		channel := &opl.channels[bank*9+reg-0xA0]
+		channel.fnum = channel.fnum&0x300 | uint16(value)
+	case reg >= 0xB0 && reg <= 0xB8:
+		channel := &opl.channels[bank*9+reg-0xB0]
+		channel.fnum = channel.fnum&0xFF | uint16(value&0x03)<<8
+		channel.block = (value >> 2) & 0x07
+		opl.setKeyOn(channel, value&0x20 != 0)
+	case reg >= 0xC0 && reg <= 0xC8:
+		channel := &opl.channels[bank*9+reg-0xC0]
+		channel.feedback = (value >> 1) & 0x07
+		channel.additive = value&0x01 != 0
+		channel.left = value&0x10 != 0
+		channel.right = value&0x20 != 0
+	}
+}
+
+func (opl *OPL) operator(bank int, offset int) *oplOperator {
+	if offset&0x07 > 5 || bank > 1 {
+		return nil
+	}
+	return &opl.operators[bank*0x20+offset]
+}
+
+// key on restarts the phase and the attack, key off releases
+func (opl *OPL) setKeyOn(channel *oplChannel, keyOn bool) {
+	if keyOn && !channel.keyOn {
+		for _, op := range []*oplOperator{channel.modulator, channel.carrier} {
+			op.phase = 0
+			op.stage = oplStageAttack
+		}
+	} else if !keyOn && channel.keyOn {
+		for _, op := range []*oplOperator{channel.modulator, channel.carrier} {
+			if op.stage != oplStageOff {
+				op.stage = oplStageRelease
+			}
+		}
+	}
+
+	channel.keyOn = keyOn
+}
+
+// seconds to go through the whole 96 dB range, rate 4 (register value 1) is the slowest
+func oplRateTime(baseTime float64, rate int) float64 {
+	return baseTime * math.Pow(2, -float64(rate-4)/4)
+}
+
+// 4 * register rate plus the key scale rate offset from block and fnum
+func (channel *oplChannel) effectiveRate(op *oplOperator, rate uint8) int {
+	if rate == 0 {
+		return 0
+	}
+
+	keyScale := int(channel.block)<<1 | int(channel.fnum>>9)&1
+	if !op.keyScaleRate {
+		keyScale >>= 2
+	}
+
+	effective := int(rate)*4 + keyScale
+	if effective > 63 {
+		effective = 63
+	}
+	return effective
+}
+
+func (opl *OPL) updateEnvelope(channel *oplChannel, op *oplOperator) {
+	dt := 1 / float64(opl.sampleRate)
+
+	switch op.stage {
+	case oplStageAttack:
+		rate := channel.effectiveRate(op, op.attackRate)
+		if rate >= 60 {
+			op.envelope = 0
+		} else if rate > 0 {
+			// the attack is exponential, fast from silence and slower close to full volume.
+			// It goes from silence to 0.1 dB, where it ends, in the attack time of the rate
+			op.envelope -= op.envelope * (6.87 / oplRateTime(2.82624, rate)) * dt
+		}
+
+		if op.envelope <= 0.1 {
+			op.envelope = 0
+			op.stage = oplStageDecay
+		}
+	case oplStageDecay:
+		rate := channel.effectiveRate(op, op.decayRate)
+		if rate > 0 {
+			op.envelope += oplSilence / oplRateTime(39.28, rate) * dt
+		}
+
+		sustainLevel := float64(op.sustainLevel) * 3
+		if op.sustainLevel == 15 {
+			sustainLevel = 93
+		}
+
+		if op.envelope >= sustainLevel {
+			op.envelope = sustainLevel
+			op.stage = oplStageSustain
+		}
+	case oplStageSustain:
+		// without the sustain flag the sound fades with the release rate even while the key is on
+		if op.sustained {
+			return
+		}
+		fallthrough
+	case oplStageRelease:
+		rate := channel.effectiveRate(op, op.releaseRate)
+		if rate > 0 {
+			op.envelope += oplSilence / oplRateTime(39.28, rate) * dt
+		}
+
+		if op.envelope >= oplSilence {
+			op.envelope = oplSilence
+			op.stage = oplStageOff
+		}
+	}
+}
+
+// attenuation in dB from the envelope, total level, key scale level and tremolo
+func (opl *OPL) attenuation(channel *oplChannel, op *oplOperator) float64 {
+	attenuation := op.envelope + float64(op.totalLevel)*0.75
+
+	if op.keyScaleLevel > 0 {
+		keyScale := oplKeyScaleLevels[channel.fnum>>6] - 8*float64(7-channel.block)
+		if keyScale > 0 {
+			// KSL 1 is 3 dB/octave, 2 is 1.5 dB/octave and 3 is 6 dB/octave
+			shifts := [4]float64{0, 0.5, 0.25, 1}
+			attenuation += keyScale * 0.75 * shifts[op.keyScaleLevel]
+		}
+	}
+
+	if op.tremolo {
+		depth := 1.0
+		if opl.deepTremolo {
+			depth = 4.8
+		}
+		attenuation += depth * (0.5 + 0.5*math.Sin(2*math.Pi*opl.tremoloPhase))
+	}
+
+	return attenuation
+}
+
+func (opl *OPL) waveform(op *oplOperator, phase float64) float64 {
+	phase -= math.Floor(phase)
+	sine := oplSineTable[int(phase*oplSineSize)&(oplSineSize-1)]
+
+	waveform := op.waveform
+	if !opl.opl3 {
+		waveform &= 0x03
+		if !opl.waveformSelect {
+			waveform = 0
+		}
+	}
+
+	switch waveform {
+	case 1:
+		if phase >= 0.5 {
+			return 0
+		}
+	case 2:
+		return math.Abs(sine)
+	case 3:
+		if math.Mod(phase, 0.5) >= 0.25 {
+			return 0
+		}
+		return math.Abs(sine)
+	case 4:
+		if phase >= 0.5 {
+			return 0
+		}
+		return oplSineTable[int(phase*2*oplSineSize)&(oplSineSize-1)]
+	case 5:
+		if phase >= 0.5 {
+			return 0
+		}
+		return math.Abs(oplSineTable[int(phase*2*oplSineSize)&(oplSineSize-1)])
+	case 6:
+		if phase >= 0.5 {
+			return 1
+		}
+		return -1
+	case 7:
+		if phase >= 0.5 {
+			return -math.Exp(-(1 - phase) * 16)
+		}
+		return math.Exp(-phase * 16)
+	}
+
+	return sine
+}
+
+func (opl *OPL) operatorOutput(channel *oplChannel, op *oplOperator, modulation float64) float64 {
+	opl.updateEnvelope(channel, op)
+
+	frequency := float64(channel.fnum) * math.Pow(2, float64(channel.block)) * oplChipRate / (1 << 20) * op.multiplier
+	if op.vibrato {
+		cents := 7.0
+		if opl.deepVibrato {
+			cents = 14
+		}
+		frequency *= math.Pow(2, cents*math.Sin(2*math.Pi*opl.vibratoPhase)/1200)
+	}
+
+	output := 0.0
+	if attenuation := opl.attenuation(channel, op); op.stage != oplStageOff && attenuation < oplSilence {
+		output = opl.waveform(op, op.phase+modulation) * math.Pow(10, -attenuation/20)
+	}
+
+	op.phase += frequency / float64(opl.sampleRate)
+	op.phase -= math.Floor(op.phase)
+
+	return output
+}
+
+func (opl *OPL) channelOutput(channel *oplChannel) float64 {
+	if channel.modulator.stage == oplStageOff && channel.carrier.stage == oplStageOff {
+		return 0
+	}
+
+	modulator := channel.modulator
+	feedback := 0.0
+	if channel.feedback > 0 {
+		feedback = (modulator.outputs[0] + modulator.outputs[1]) / 2 * math.Pow(2, float64(channel.feedback)-6)
+	}
+
+	modulatorOutput := opl.operatorOutput(channel, modulator, feedback)
+	modulator.outputs[1] = modulator.outputs[0]
+	modulator.outputs[0] = modulatorOutput
+
+	if channel.additive {
+		return modulatorOutput + opl.operatorOutput(channel, channel.carrier, 0)
+	}
+
+	return opl.operatorOutput(channel, channel.carrier, modulatorOutput*oplModulationDepth)
+}
+
+// appends count stereo frames to samples, OPL2 mode plays every channel on both sides
+func (opl *OPL) Generate(samples []int16, count int) []int16 {
+	for i := 0; i < count; i++ {
+		left, right := 0.0, 0.0
+
+		for idx := 0; idx < opl.ChannelCount(); idx++ {
+			channel := &opl.channels[idx]
+			output := opl.channelOutput(channel) * oplChannelScale
+
+			if !opl.opl3 || channel.left {
+				left += output
+			}
+			if !opl.opl3 || channel.right {
+				right += output
+			}
+		}
+
+		samples = append(samples, clampSample(left), clampSample(right))
+
+		opl.tremoloPhase += 3.7 / float64(opl.sampleRate)
+		opl.tremoloPhase -= math.Floor(opl.tremoloPhase)
+		opl.vibratoPhase += 6.1 / float64(opl.sampleRate)
+		opl.vibratoPhase -= math.Floor(opl.vibratoPhase)
+	}
+
+	return samples
+}
+
+func clampSample(value float64) int16 {
+	if value > math.MaxInt16 {
+		return math.MaxInt16
+	}
+	if value < math.MinInt16 {
+		return math.MinInt16
+	}
+	return int16(value)
+}
@@ -0,0 +1,413 @@
+package wadloader
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// the songs are played like the DMX sound library did on a Sound Blaster: every
+// note takes an OPL channel (voice) programmed with its GENMIDI instrument and,
+// when none is free, the voice of the highest MIDI channel is taken over
+const (
+	DefaultMusicSampleRate = 44100
+	DefaultMusicMaxSeconds = 600
+
+	// time given to the last notes to fade out
+	musicReleaseTail = 2.0
+
+	dmxDefaultChannelVolume = 100
+	midiDefaultPan          = 64
+)
+
+type MusicRenderOptions struct {
+	SampleRate int
+	// songs are cut at this length, MUS songs loop forever in game
+	MaxSeconds float64
+	// 18 voices and stereo panning instead of the 9 mono voices of an OPL2
+	OPL3 bool
+}
+
+type dmxVoice struct {
+	index int
+
+	channel    int
+	key        int
+	note       int
+	noteVolume int
+	instrument *GenMIDIInstrument
+	// 0 for the main voice, 1 for the second voice of double voice instruments
+	instrumentVoice int
+	active          bool
+}
+
+type dmxChannel struct {
+	program int
+	volume  int
+	pan     int
+	// in 1/32 semitones
+	bend int
+}
+
+type dmxPlayer struct {
+	opl      *OPL
+	genMIDI  *GenMIDI
+	opl3     bool
+	voices   []*dmxVoice
+	freeList []*dmxVoice
+	channels [16]dmxChannel
+}
+
+func newDMXPlayer(genMIDI *GenMIDI, options MusicRenderOptions) *dmxPlayer {
+	player := &dmxPlayer{opl: NewOPL(options.SampleRate), genMIDI: genMIDI, opl3: options.OPL3}
+
+	// waveform selection for OPL2, OPL3 mode adds the second register bank
+	player.opl.WriteRegister(0x01, 0x20)
+	if options.OPL3 {
+		player.opl.WriteRegister(0x105, 0x01)
+	}
+
+	for idx := 0; idx < player.opl.ChannelCount(); idx++ {
+		voice := &dmxVoice{index: idx}
+		player.voices = append(player.voices, voice)
+		player.freeList = append(player.freeList, voice)
+	}
+
+	for idx := range player.channels {
+		player.channels[idx] = dmxChannel{volume: dmxDefaultChannelVolume, pan: midiDefaultPan}
+	}
+
+	return player
+}
+
+// register of the channel (0xA0, 0xB0, 0xC0) or operator (0x20...) of a voice
+func (voice *dmxVoice) channelRegister(base uint16) uint16 {
+	return uint16(voice.index/9)<<8 | base + uint16(voice.index%9)
+}
+
+func (voice *dmxVoice) operatorRegister(base uint16, carrier bool) uint16 {
+	offset := uint16(oplChannelOperators[voice.index%9])
+	if carrier {
+		offset += 3
+	}
+	return uint16(voice.index/9)<<8 | base + offset
+}
+
+func (dp *dmxPlayer) getFreeVoice() *dmxVoice {
+	if len(dp.freeList) < 1 {
+		return nil
+	}
+
+	voice := dp.freeList[0]
+	dp.freeList = dp.freeList[1:]
+	voice.active = true
+
+	return voice
+}
+
+func (dp *dmxPlayer) releaseVoice(voice *dmxVoice) {
+	dp.opl.WriteRegister(voice.channelRegister(0xB0), uint8(dp.opl.channels[voice.index].block<<2)|uint8(dp.opl.channels[voice.index].fnum>>8))
+	voice.active = false
+	dp.freeList = append(dp.freeList, voice)
+}
+
+// the second voices of double voice instruments go first, then the highest MIDI channel
+func (dp *dmxPlayer) replaceExistingVoice() *dmxVoice {
+	var result *dmxVoice
+
+	for _, voice := range dp.voices {
+		if !voice.active {
+			continue
+		}
+		if result == nil || voice.instrumentVoice != 0 || voice.channel >= result.channel {
+			result = voice
+		}
+	}
+
+	if result == nil {
+		return nil
+	}
+
+	dp.releaseVoice(result)
+
+	return dp.getFreeVoice()
+}
+
+func (dp *dmxPlayer) programVoice(voice *dmxVoice) {
+	oplVoice := voice.instrument.Voices[voice.instrumentVoice]
+
+	for _, carrier := range []bool{false, true} {
+		op := oplVoice.Modulator
+		if carrier {
+			op = oplVoice.Carrier
+		}
+
+		dp.opl.WriteRegister(voice.operatorRegister(0x20, carrier), op.Characteristic)
+		dp.opl.WriteRegister(voice.operatorRegister(0x60, carrier), op.AttackDecay)
+		dp.opl.WriteRegister(voice.operatorRegister(0x80, carrier), op.SustainRelease)
+		dp.opl.WriteRegister(voice.operatorRegister(0xE0, carrier), op.Waveform)
+	}
+
+	dp.setVoiceVolume(voice)
+	dp.setVoicePan(voice)
+}
+
+// DMX maps volumes through a curve that rises quickly and flattens near the top
+func dmxVolumeCurve(volume int) int {
+	remaining := 127 - volume
+	return 127 - remaining*remaining/127
+}
+
+// the carrier (and the modulator of additive voices) gets quieter with the note
+// and channel volumes, scaling the part of the range the instrument level leaves
+func (dp *dmxPlayer) setVoiceVolume(voice *dmxVoice) {
+	oplVoice := voice.instrument.Voices[voice.instrumentVoice]
+	volume := dmxVolumeCurve(voice.noteVolume) * dmxVolumeCurve(dp.channels[voice.channel].volume) / 127
+
+	setLevel := func(op OPLOperator, carrier bool) {
+		level := 0x3F - (0x3F-int(op.Level&0x3F))*volume/127
+		dp.opl.WriteRegister(voice.operatorRegister(0x40, carrier), op.KeyScale&0xC0|uint8(level))
+	}
+
+	setLevel(oplVoice.Carrier, true)
+
+	if oplVoice.Feedback&0x01 != 0 {
+		setLevel(oplVoice.Modulator, false)
+	} else {
+		dp.opl.WriteRegister(voice.operatorRegister(0x40, false), oplVoice.Modulator.KeyScale&0xC0|oplVoice.Modulator.Level&0x3F)
+	}
+}
+
+// OPL3 pans hard left or right past a third of the range, OPL2 is mono
+func (dp *dmxPlayer) setVoicePan(voice *dmxVoice) {
+	oplVoice := voice.instrument.Voices[voice.instrumentVoice]
+	stereo := uint8(0x30)
+
+	if dp.opl3 {
+		pan := dp.channels[voice.channel].pan
+		if pan >= 96 {
+			stereo = 0x20
+		} else if pan <= 48 {
+			stereo = 0x10
+		}
+	}
+
+	dp.opl.WriteRegister(voice.channelRegister(0xC0), oplVoice.Feedback&0x0F|stereo)
+}
+
+// notes are kept inside the 8 octaves of the chip, the second voice is detuned by the fine tuning
+func (dp *dmxPlayer) voiceFrequency(voice *dmxVoice) (uint16, uint8) {
+	note := voice.note
+	if !voice.instrument.FixedPitch() {
+		note += int(voice.instrument.Voices[voice.instrumentVoice].NoteOffset)
+	}
+	for note < 0 {
+		note += 12
+	}
+	for note > 95 {
+		note -= 12
+	}
+
+	semitones := float64(note) + float64(dp.channels[voice.channel].bend)/32
+	if voice.instrumentVoice == 1 {
+		semitones += (float64(voice.instrument.FineTuning)/2 - 64) / 32
+	}
+
+	frequency := 440 * math.Pow(2, (semitones-69)/12)
+
+	for block := 0; block < 8; block++ {
+		fnum := frequency * (1 << 20) / oplChipRate / math.Pow(2, float64(block))
+		if fnum < 1024 {
+			return uint16(fnum), uint8(block)
+		}
+	}
+
+	return 1023, 7
+}
+
+func (dp *dmxPlayer) updateVoiceFrequency(voice *dmxVoice, keyOn bool) {
+	fnum, block := dp.voiceFrequency(voice)
+
+	value := block<<2 | uint8(fnum>>8)
+	if keyOn {
+		value |= 0x20
+	}
+
+	dp.opl.WriteRegister(voice.channelRegister(0xA0), uint8(fnum))
+	dp.opl.WriteRegister(voice.channelRegister(0xB0), value)
+}
+
+func (dp *dmxPlayer) noteOn(channel int, key int, velocity int) {
+	percussion := channel == MIDIPercussionChannel
+
+	var instrument *GenMIDIInstrument
+	if percussion {
+		instrument = dp.genMIDI.Instrument(key, true)
+	} else {
+		instrument = dp.genMIDI.Instrument(dp.channels[channel].program, false)
+	}
+	if instrument == nil {
+		return
+	}
+
+	note := key
+	if instrument.FixedPitch() {
+		note = int(instrument.FixedNote)
+	}
+
+	voiceCount := 1
+	if instrument.DoubleVoice() {
+		voiceCount = 2
+	}
+
+	for instrumentVoice := 0; instrumentVoice < voiceCount; instrumentVoice++ {
+		voice := dp.getFreeVoice()
+
+		// only the main voice takes over a playing one
+		if voice == nil && instrumentVoice == 0 {
+			voice = dp.replaceExistingVoice()
+		}
+		if voice == nil {
+			return
+		}
+
+		voice.channel = channel
+		voice.key = key
+		voice.note = note
+		voice.noteVolume = velocity
+		voice.instrument = instrument
+		voice.instrumentVoice = instrumentVoice
+
+		dp.programVoice(voice)
+		dp.updateVoiceFrequency(voice, true)
+	}
+}
+
+func (dp *dmxPlayer) noteOff(channel int, key int) {
+	for _, voice := range dp.voices {
+		if voice.active && voice.channel == channel && voice.key == key {
+			dp.releaseVoice(voice)
+		}
+	}
+}
+
+func (dp *dmxPlayer) allNotesOff(channel int) {
+	for _, voice := range dp.voices {
+		if voice.active && voice.channel == channel {
+			dp.releaseVoice(voice)
+		}
+	}
+}
+
+func (dp *dmxPlayer) controller(channel int, controller int, value int) {
+	switch controller {
+	case midiControllerVolume:
+		dp.channels[channel].volume = value
+		dp.forChannelVoices(channel, dp.setVoiceVolume)
+	case midiControllerPan:
+		dp.channels[channel].pan = value
+		dp.forChannelVoices(channel, dp.setVoicePan)
+	case midiControllerAllSoundsOff, midiControllerAllNotesOff:
+		dp.allNotesOff(channel)
+	case midiControllerResetAll:
+		dp.channels[channel].bend = 0
+		dp.channels[channel].pan = midiDefaultPan
+		dp.forChannelVoices(channel, func(voice *dmxVoice) { dp.updateVoiceFrequency(voice, true) })
+	}
+}
+
+func (dp *dmxPlayer) forChannelVoices(channel int, apply func(voice *dmxVoice)) {
+	for _, voice := range dp.voices {
+		if voice.active && voice.channel == channel {
+			apply(voice)
+		}
+	}
+}
+
+func (dp *dmxPlayer) handleEvent(event SongEvent) {
+	channel := event.Channel & 0x0F
+
+	switch event.Kind {
+	case SongNoteOn:
+		dp.noteOn(channel, event.Data1, event.Data2)
+	case SongNoteOff:
+		dp.noteOff(channel, event.Data1)
+	case SongProgram:
+		dp.channels[channel].program = event.Data1
+	case SongController:
+		dp.controller(channel, event.Data1, event.Data2)
+	case SongPitchBend:
+		dp.channels[channel].bend = (event.Data1 - midiPitchBendCenter) / 128
+		dp.forChannelVoices(channel, func(voice *dmxVoice) { dp.updateVoiceFrequency(voice, true) })
+	}
+}
+
+// stereo 16 bits PCM, played once up to the max length plus a short fade out tail
+func RenderSong(song Song, genMIDI *GenMIDI, options MusicRenderOptions) (WAVAudio, error) {
+	if options.SampleRate < 1 {
+		return WAVAudio{}, errors.New("[Error] RenderSong: Invalid sample rate")
+	}
+
+	player := newDMXPlayer(genMIDI, options)
+	audio := WAVAudio{SampleRate: options.SampleRate, Channels: 2}
+
+	endTime := song.Duration + musicReleaseTail
+	if options.MaxSeconds > 0 && endTime > options.MaxSeconds {
+		endTime = options.MaxSeconds
+	}
+
+	frames := 0
+	renderUntil := func(time float64) {
+		target := int(time * float64(options.SampleRate))
+		if target > frames {
+			audio.Samples = player.opl.Generate(audio.Samples, target-frames)
+			frames = target
+		}
+	}
+
+	for _, event := range song.Events {
+		if event.Time > endTime {
+			break
+		}
+
+		renderUntil(event.Time)
+		player.handleEvent(event)
+	}
+
+	renderUntil(endTime)
+
+	return audio, nil
+}
+
+func (wl *WADLoader) RenderAllSongs(folderName string, genMIDI *GenMIDI, options MusicRenderOptions) error {
+	if len(wl.Music) < 1 {
+		return errors.New("[Error] RenderAllSongs: No music data inside WAD Loader")
+	}
+
+	folderName, err := createFolder(folderName)
+	if err != nil {
+		return errors.New("[Error] RenderAllSongs: Cannot create the target folder - " + err.Error())
+	}
+
+	return runWorkers(len(wl.Music), wl.workerCount(), func(idx int) error {
+		musicLump := wl.Music[idx]
+
+		lumpData, err := wl.ReadLumpData(musicLump.lump)
+		if err != nil {
+			return err
+		}
+
+		song, err := ParseSong(musicLump.format, lumpData)
+		if err != nil {
+			fmt.Println("[Warn] RenderAllSongs: Skipping " + musicLump.name + " - " + err.Error())
+			return nil
+		}
+
+		audio, err := RenderSong(song, genMIDI, options)
+		if err != nil {
+			return err
+		}
+
+		return audio.WriteFile(folderName + "/" + musicLump.name + ".wav")
+	})
+}
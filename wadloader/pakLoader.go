@@ -0,0 +1,255 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// Quake PAK archives: "PACK" header and a directory of 64 bytes entries with full paths
+const pakEntryByteSize = 64
+
+type PAKLoader struct {
+	PAKFilename string
+	PAKReader   io.ReaderAt
+	PAKSize     int64
+	PAKHeader   PAKHeader
+	Entries     []PAKEntry
+
+	pakCloser io.Closer
+}
+
+type PAKHeader struct {
+	PakType         [4]byte
+	DirectoryOffset uint32
+	DirectorySize   uint32
+}
+
+type PAKEntry struct {
+	EntryName   [56]byte
+	EntryOffset uint32
+	EntrySize   uint32
+}
+
+func (e PAKEntry) Name() string {
+	nameEnd := bytes.IndexByte(e.EntryName[:], 0)
+	if nameEnd == -1 {
+		nameEnd = len(e.EntryName)
+	}
+	return string(e.EntryName[:nameEnd])
+}
+
+func (pl *PAKLoader) OpenAndLoad(pakFilename string) error {
+	pl.PAKFilename = pakFilename
+
+	pakFile, err := os.Open(pakFilename)
+	if err != nil {
+		return errors.New("[Error] OpenAndLoad: Couldn't open the PAK file - " + err.Error())
+	}
+
+	fileInfo, err := pakFile.Stat()
+	if err != nil {
+		pakFile.Close()
+		return errors.New("[Error] OpenAndLoad: Couldn't get the PAK file size - " + err.Error())
+	}
+
+	err = pl.LoadFromReaderAt(pakFile, fileInfo.Size())
+	if err != nil {
+		pakFile.Close()
+		return err
+	}
+
+	pl.pakCloser = pakFile
+
+	return nil
+}
+
+func (pl *PAKLoader) Close() error {
+	if pl.pakCloser == nil {
+		return nil
+	}
+
+	err := pl.pakCloser.Close()
+	pl.pakCloser = nil
+
+	return err
+}
+
+func (pl *PAKLoader) LoadFromReaderAt(r io.ReaderAt, size int64) error {
+	if size < 12 {
+		return errors.New("[Error] LoadFromReaderAt: Not enough data to read the PAK header")
+	}
+
+	pl.PAKReader = r
+	pl.PAKSize = size
+
+	err := binary.Read(io.NewSectionReader(r, 0, 12), binary.LittleEndian, &pl.PAKHeader)
+	if err != nil {
+		return errors.New("[Error] LoadFromReaderAt: Invalid data when reading the PAK Header - " + err.Error())
+	}
+
+	if string(pl.PAKHeader.PakType[:]) != "PACK" {
+		return errors.New("[Error] LoadFromReaderAt: Not a PAK file")
+	}
+
+	return pl.readEntries()
+}
+
+func (pl *PAKLoader) readEntries() error {
+	dirOffset := int64(pl.PAKHeader.DirectoryOffset)
+	dirSize := int64(pl.PAKHeader.DirectorySize)
+
+	if dirSize%pakEntryByteSize != 0 || dirOffset+dirSize > pl.PAKSize {
+		return errors.New("[Error] readEntries: Invalid PAK directory")
+	}
+
+	pl.Entries = make([]PAKEntry, dirSize/pakEntryByteSize)
+
+	err := binary.Read(io.NewSectionReader(pl.PAKReader, dirOffset, dirSize), binary.LittleEndian, &pl.Entries)
+	if err != nil {
+		return errors.New("[Error] readEntries: Invalid data when reading the PAK directory - " + err.Error())
+	}
+
+	return nil
+}
+
+func (pl *PAKLoader) EntryReader(entry PAKEntry) (*io.SectionReader, error) {
+	if int64(entry.EntryOffset)+int64(entry.EntrySize) > pl.PAKSize {
+		return nil, errors.New("[Error] EntryReader: " + entry.Name() + " points outside of the PAK file")
+	}
+
+	return io.NewSectionReader(pl.PAKReader, int64(entry.EntryOffset), int64(entry.EntrySize)), nil
+}
+
+func (pl *PAKLoader) ReadEntryData(entry PAKEntry) ([]byte, error) {
+	entryReader, err := pl.EntryReader(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	data := make([]byte, entry.EntrySize)
+
+	_, err = io.ReadFull(entryReader, data)
+	if err != nil {
+		return nil, errors.New("[Error] ReadEntryData: Cannot read " + entry.Name() + " - " + err.Error())
+	}
+
+	return data, nil
+}
+
+// keeps the folder tree of the entry paths inside outputFolder
+func (pl *PAKLoader) ExtractAll(outputFolder string) error {
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	for _, entry := range pl.Entries {
+		err := extractArchiveEntry(outputFolder, entry.Name(), func() ([]byte, error) {
+			return pl.ReadEntryData(entry)
+		})
+		if err != nil {
+			return errors.New("[Error] ExtractAll: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
+// entry paths come from the archive, the ones escaping outputFolder are refused
+func extractArchiveEntry(outputFolder string, entryPath string, readData func() ([]byte, error)) error {
+	cleanPath := path.Clean("/" + strings.ReplaceAll(entryPath, "\\", "/"))
+	if cleanPath == "/" {
+		return errors.New("[Error] extractArchiveEntry: Invalid entry path " + entryPath)
+	}
+
+	targetPath := filepath.Join(outputFolder, filepath.FromSlash(cleanPath[1:]))
+
+	err := os.MkdirAll(filepath.Dir(targetPath), 0755)
+	if err != nil {
+		return errors.New("[Error] extractArchiveEntry: Cannot create the folder for " + entryPath + " - " + err.Error())
+	}
+
+	data, err := readData()
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(targetPath, data, 0644)
+	if err != nil {
+		return errors.New("[Error] extractArchiveEntry: Cannot write " + targetPath + " - " + err.Error())
+	}
+
+	return nil
+}
+
+func (pl *PAKLoader) EmbeddedWADs() []PAKEntry {
+	var wadEntries []PAKEntry
+
+	for _, entry := range pl.Entries {
+		if strings.EqualFold(path.Ext(entry.Name()), ".wad") {
+			wadEntries = append(wadEntries, entry)
+		}
+	}
+
+	return wadEntries
+}
+
+// returns ArchiveTypeWAD or ArchiveTypeWAD2 depending on the embedded WAD signature
+func (pl *PAKLoader) EmbeddedWADType(entry PAKEntry) (string, error) {
+	entryReader, err := pl.EntryReader(entry)
+	if err != nil {
+		return "", err
+	}
+
+	signature := make([]byte, 4)
+
+	_, err = io.ReadFull(entryReader, signature)
+	if err != nil {
+		return "", errors.New("[Error] EmbeddedWADType: Cannot read the signature of " + entry.Name())
+	}
+
+	archiveType, err := detectArchiveTypeFromSignature(signature)
+	if err != nil || (archiveType != ArchiveTypeWAD && archiveType != ArchiveTypeWAD2) {
+		return "", errors.New("[Error] EmbeddedWADType: " + entry.Name() + " is not a WAD file")
+	}
+
+	return archiveType, nil
+}
+
+func (pl *PAKLoader) OpenEmbeddedWAD(entry PAKEntry) (*WADLoader, error) {
+	entryReader, err := pl.EntryReader(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	wad := &WADLoader{WADFilename: pl.PAKFilename + ":" + entry.Name()}
+
+	err = wad.LoadFromReaderAt(entryReader, entryReader.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	return wad, nil
+}
+
+func (pl *PAKLoader) OpenEmbeddedWAD2(entry PAKEntry) (*WAD2Loader, error) {
+	entryReader, err := pl.EntryReader(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	wad := &WAD2Loader{WADFilename: pl.PAKFilename + ":" + entry.Name()}
+
+	err = wad.LoadFromReaderAt(entryReader, entryReader.Size())
+	if err != nil {
+		return nil, err
+	}
+
+	return wad, nil
+}
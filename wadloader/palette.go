@@ -0,0 +1,198 @@
+package wadloader
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	PaletteFormatJASC = "jasc"
+	PaletteFormatGIMP = "gpl"
+	PaletteFormatACT  = "act"
+	PaletteFormatRaw  = "raw"
+	PaletteFormatPNG  = "png"
+
+	paletteSwatchCellSize = 16
+)
+
+// .pal files are written as JASC, use PaletteFormatRaw for the 768 bytes variant
+func PaletteFormatFromFilename(filename string) string {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".gpl":
+		return PaletteFormatGIMP
+	case ".act":
+		return PaletteFormatACT
+	case ".png":
+		return PaletteFormatPNG
+	case ".lmp", ".raw", ".dat":
+		return PaletteFormatRaw
+	}
+
+	return PaletteFormatJASC
+}
+
+// the format is guessed from the file contents: JASC and GIMP text headers, PNG swatches
+// and raw 256 RGB triplets, like Quake's palette.lmp, a PLAYPAL entry or an .act file
+func ImportPalette(filename string) (Palette, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return Palette{}, errors.New("[Error] ImportPalette: Cannot read the palette file - " + err.Error())
+	}
+
+	switch {
+	case bytes.HasPrefix(data, []byte("JASC-PAL")):
+		return parseTextPalette(data, 3, "JASC")
+	case bytes.HasPrefix(data, []byte("GIMP Palette")):
+		return parseTextPalette(data, 1, "GIMP")
+	case bytes.HasPrefix(data, []byte("\x89PNG")):
+		return parseSwatchPalette(data)
+	}
+
+	return PaletteFromRawData(data)
+}
+
+// both formats list one "R G B" color per line after their header lines,
+// GIMP adds Name/Columns lines, comments and a color name after the values
+func parseTextPalette(data []byte, headerLines int, formatName string) (Palette, error) {
+	var palette Palette
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	colorIdx := 0
+
+	for lineIdx := 0; scanner.Scan() && colorIdx < 256; lineIdx++ {
+		line := strings.TrimSpace(scanner.Text())
+		if lineIdx < headerLines || line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+
+		var components [3]uint8
+		isColor := true
+
+		for i := 0; i < 3; i++ {
+			value, err := strconv.Atoi(fields[i])
+			if err != nil || value < 0 || value > 255 {
+				isColor = false
+				break
+			}
+			components[i] = uint8(value)
+		}
+
+		if !isColor {
+			continue
+		}
+
+		palette[colorIdx] = PaletteColor{components[0], components[1], components[2]}
+		colorIdx++
+	}
+
+	if colorIdx < 256 {
+		fmt.Printf("[Warn] ImportPalette: The %s palette only has %v colors, the rest are black\n", formatName, colorIdx)
+	}
+
+	if colorIdx < 1 {
+		return palette, errors.New("[Error] ImportPalette: No colors found in the " + formatName + " palette")
+	}
+
+	return palette, nil
+}
+
+// the center pixel of each cell of a 16x16 grid
+func parseSwatchPalette(data []byte) (Palette, error) {
+	var palette Palette
+
+	swatchImg, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return palette, errors.New("[Error] ImportPalette: Cannot decode the swatch PNG - " + err.Error())
+	}
+
+	bounds := swatchImg.Bounds()
+	if bounds.Dx() < 16 || bounds.Dy() < 16 {
+		return palette, errors.New("[Error] ImportPalette: The swatch PNG must be at least 16x16 pixels")
+	}
+
+	cellWidth := bounds.Dx() / 16
+	cellHeight := bounds.Dy() / 16
+
+	for idx := range palette {
+		x := bounds.Min.X + (idx%16)*cellWidth + cellWidth/2
+		y := bounds.Min.Y + (idx/16)*cellHeight + cellHeight/2
+
+		pixelColor := color.RGBAModel.Convert(swatchImg.At(x, y)).(color.RGBA)
+		palette[idx] = PaletteColor{pixelColor.R, pixelColor.G, pixelColor.B}
+	}
+
+	return palette, nil
+}
+
+func ExportPalette(palette Palette, filename string, format string) error {
+	var paletteData []byte
+
+	switch format {
+	case PaletteFormatJASC:
+		var text bytes.Buffer
+		text.WriteString("JASC-PAL\r\n0100\r\n256\r\n")
+		for _, paletteColor := range palette {
+			fmt.Fprintf(&text, "%d %d %d\r\n", paletteColor.Red, paletteColor.Green, paletteColor.Blue)
+		}
+		paletteData = text.Bytes()
+	case PaletteFormatGIMP:
+		var text bytes.Buffer
+		name := strings.TrimSuffix(filepath.Base(filename), filepath.Ext(filename))
+		fmt.Fprintf(&text, "GIMP Palette\nName: %s\nColumns: 16\n#\n", name)
+		for idx, paletteColor := range palette {
+			fmt.Fprintf(&text, "%3d %3d %3d\tIndex %d\n", paletteColor.Red, paletteColor.Green, paletteColor.Blue, idx)
+		}
+		paletteData = text.Bytes()
+	case PaletteFormatACT, PaletteFormatRaw:
+		// .act files may carry a color count and transparent index, 256 colors don't need them
+		paletteData = PalettesToLumpData([]Palette{palette})
+	case PaletteFormatPNG:
+		var pngData bytes.Buffer
+		err := png.Encode(&pngData, PaletteToSwatchImage(palette))
+		if err != nil {
+			return errors.New("[Error] ExportPalette: Cannot encode the swatch PNG - " + err.Error())
+		}
+		paletteData = pngData.Bytes()
+	default:
+		return errors.New("[Error] ExportPalette: Unknown palette format " + format)
+	}
+
+	err := os.WriteFile(filename, paletteData, 0644)
+	if err != nil {
+		return errors.New("[Error] ExportPalette: Cannot write the palette file - " + err.Error())
+	}
+
+	return nil
+}
+
+func PaletteToSwatchImage(palette Palette) *image.RGBA {
+	swatchImg := image.NewRGBA(image.Rect(0, 0, 16*paletteSwatchCellSize, 16*paletteSwatchCellSize))
+
+	for idx, paletteColor := range palette {
+		cellColor := color.RGBA{paletteColor.Red, paletteColor.Green, paletteColor.Blue, 255}
+		cellX := (idx % 16) * paletteSwatchCellSize
+		cellY := (idx / 16) * paletteSwatchCellSize
+
+		for y := 0; y < paletteSwatchCellSize; y++ {
+			for x := 0; x < paletteSwatchCellSize; x++ {
+				swatchImg.Set(cellX+x, cellY+y, cellColor)
+			}
+		}
+	}
+
+	return swatchImg
+}
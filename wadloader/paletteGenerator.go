@@ -0,0 +1,147 @@
+package wadloader
+
+// same maths as id's palette tools: PLAYPAL tints the base palette towards
+// a color and COLORMAP fades it to black, every result mapped back to the
+// nearest color of the base palette
+const (
+	PlaypalRedCount   = 8
+	PlaypalGoldCount  = 4
+	PlaypalGreenCount = 1
+	ColormapCount     = 34
+)
+
+type tintTarget struct {
+	red, green, blue int
+}
+
+var (
+	painTint      = tintTarget{255, 0, 0}
+	pickupTint    = tintTarget{215, 186, 69}
+	radiationTint = tintTarget{0, 256, 0}
+)
+
+// palette 0 is the base, 1-8 pain red, 9-12 item pickup gold and 13 the radiation suit green
+func GeneratePlaypal(base Palette) []Palette {
+	palettes := []Palette{base}
+
+	for i := 1; i <= PlaypalRedCount; i++ {
+		palettes = append(palettes, tintPalette(base, painTint, float64(i)/9))
+	}
+
+	for i := 1; i <= PlaypalGoldCount; i++ {
+		palettes = append(palettes, tintPalette(base, pickupTint, float64(i)*0.125))
+	}
+
+	for i := 1; i <= PlaypalGreenCount; i++ {
+		palettes = append(palettes, tintPalette(base, radiationTint, float64(i)*0.125))
+	}
+
+	return palettes
+}
+
+func tintPalette(base Palette, target tintTarget, amount float64) Palette {
+	var tinted Palette
+
+	for idx, baseColor := range base {
+		tinted[idx] = PaletteColor{
+			Red:   tintComponent(baseColor.Red, target.red, amount),
+			Green: tintComponent(baseColor.Green, target.green, amount),
+			Blue:  tintComponent(baseColor.Blue, target.blue, amount),
+		}
+	}
+
+	return tinted
+}
+
+func tintComponent(value uint8, target int, amount float64) uint8 {
+	tinted := int(float64(value) + float64(target-int(value))*amount)
+	if tinted > 255 {
+		return 255
+	}
+	if tinted < 0 {
+		return 0
+	}
+	return uint8(tinted)
+}
+
+// 32 light levels fading to black, the inverted grayscale invulnerability map and an all black map
+func GenerateColormaps(palette Palette) []Colormap {
+	colormaps := make([]Colormap, 0, ColormapCount)
+
+	for level := 0; level < ColormapLightLevels; level++ {
+		var colormap Colormap
+		brightness := float64(ColormapLightLevels-level) / ColormapLightLevels
+
+		for idx, baseColor := range palette {
+			colormap[idx] = NearestPaletteColor(palette,
+				int(float64(baseColor.Red)*brightness+0.5),
+				int(float64(baseColor.Green)*brightness+0.5),
+				int(float64(baseColor.Blue)*brightness+0.5))
+		}
+
+		colormaps = append(colormaps, colormap)
+	}
+
+	var invulnerability Colormap
+	for idx, baseColor := range palette {
+		gray := (0.2989*float64(baseColor.Red) + 0.587*float64(baseColor.Green) + 0.114*float64(baseColor.Blue)) / 255
+		grayValue := int((1-gray)*255 + 0.5)
+		invulnerability[idx] = NearestPaletteColor(palette, grayValue, grayValue, grayValue)
+	}
+
+	colormaps = append(colormaps, invulnerability)
+
+	var black Colormap
+	blackIdx := NearestPaletteColor(palette, 0, 0, 0)
+	for idx := range black {
+		black[idx] = blackIdx
+	}
+
+	return append(colormaps, black)
+}
+
+// squared RGB distance, the first color wins on ties
+func NearestPaletteColor(palette Palette, red int, green int, blue int) uint8 {
+	bestIdx := 0
+	bestDistance := -1
+
+	for idx, paletteColor := range palette {
+		dr := int(paletteColor.Red) - red
+		dg := int(paletteColor.Green) - green
+		db := int(paletteColor.Blue) - blue
+
+		distance := dr*dr + dg*dg + db*db
+		if bestDistance < 0 || distance < bestDistance {
+			bestIdx = idx
+			bestDistance = distance
+		}
+
+		if distance == 0 {
+			break
+		}
+	}
+
+	return uint8(bestIdx)
+}
+
+func PalettesToLumpData(palettes []Palette) []byte {
+	lumpData := make([]byte, 0, len(palettes)*768)
+
+	for _, palette := range palettes {
+		for _, paletteColor := range palette {
+			lumpData = append(lumpData, paletteColor.Red, paletteColor.Green, paletteColor.Blue)
+		}
+	}
+
+	return lumpData
+}
+
+func ColormapsToLumpData(colormaps []Colormap) []byte {
+	lumpData := make([]byte, 0, len(colormaps)*256)
+
+	for _, colormap := range colormaps {
+		lumpData = append(lumpData, colormap[:]...)
+	}
+
+	return lumpData
+}
@@ -0,0 +1,155 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// the PIT clock, the speaker plays at 1193181 / divisor Hz
+	pitFrequency = 1193181
+	// one tone per tic of the 140 Hz timer
+	pcSpeakerTickRate   = 140
+	PCSpeakerSampleRate = 44100
+	pcSpeakerAmplitude  = 8000
+)
+
+// PIT divisors of the DMX tone table, roughly a quarter tone per step from 175 Hz (6818), tone 0 is silence
+var pcSpeakerDivisors = [128]int{
+	0, 6818, 6628, 6449, 6279, 6087, 5906, 5736, 5575, 5423, 5279, 5120, 4971, 4830, 4697, 4554,
+	4435, 4307, 4186, 4058, 3950, 3836, 3728, 3615, 3519, 3418, 3323, 3224, 3131, 3043, 2960, 2875,
+	2794, 2711, 2633, 2560, 2485, 2415, 2348, 2281, 2213, 2153, 2089, 2032, 1975, 1918, 1864, 1810,
+	1757, 1709, 1659, 1612, 1565, 1521, 1478, 1435, 1395, 1355, 1316, 1280, 1242, 1207, 1173, 1140,
+	1107, 1075, 1045, 1015, 986, 959, 931, 905, 879, 854, 829, 806, 783, 760, 739, 718,
+	697, 677, 658, 640, 621, 604, 586, 570, 553, 538, 522, 507, 493, 479, 465, 452,
+	439, 427, 415, 403, 391, 380, 369, 359, 348, 339, 329, 319, 310, 302, 293, 285,
+	276, 269, 261, 253, 246, 239, 232, 226, 219, 213, 207, 201, 195, 190, 184, 179,
+}
+
+// DP* lumps: a format 0 header, the tone count and one tone index per tic
+type PCSpeakerSound struct {
+	name  string
+	tones []uint8
+}
+
+type pcSpeakerHeader struct {
+	Format    uint16
+	ToneCount uint16
+}
+
+func (ps *PCSpeakerSound) Name() string {
+	return ps.name
+}
+
+// seconds, one tone lasts a tic
+func (ps *PCSpeakerSound) Duration() float64 {
+	return float64(len(ps.tones)) / pcSpeakerTickRate
+}
+
+func ParsePCSpeakerSound(name string, data []byte) (PCSpeakerSound, error) {
+	sound := PCSpeakerSound{name: name}
+
+	var header pcSpeakerHeader
+	err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header)
+	if err != nil || header.Format != 0 {
+		return sound, errors.New("[Error] ParsePCSpeakerSound: " + name + " has no format 0 header")
+	}
+
+	if 4+int(header.ToneCount) > len(data) {
+		return sound, errors.New("[Error] ParsePCSpeakerSound: " + name + " declares more tones than it holds")
+	}
+
+	sound.tones = data[4 : 4+int(header.ToneCount)]
+
+	return sound, nil
+}
+
+// square wave, the phase carries over between tics playing the same tone
+func (ps *PCSpeakerSound) Synthesize(sampleRate int) WAVAudio {
+	audio := WAVAudio{SampleRate: sampleRate, Channels: 1}
+	audio.Samples = make([]int16, 0, len(ps.tones)*sampleRate/pcSpeakerTickRate+1)
+
+	phase := 0.0
+	tickEnd := 0.0
+
+	for _, tone := range ps.tones {
+		tickEnd += float64(sampleRate) / pcSpeakerTickRate
+		frequency := 0.0
+		if tone > 0 && int(tone) < len(pcSpeakerDivisors) {
+			frequency = pitFrequency / float64(pcSpeakerDivisors[tone])
+		}
+
+		for float64(len(audio.Samples)) < tickEnd {
+			if frequency == 0 {
+				audio.Samples = append(audio.Samples, 0)
+				phase = 0
+				continue
+			}
+
+			sample := int16(pcSpeakerAmplitude)
+			if phase >= 0.5 {
+				sample = -pcSpeakerAmplitude
+			}
+			audio.Samples = append(audio.Samples, sample)
+
+			phase += frequency / float64(sampleRate)
+			phase -= math.Floor(phase)
+		}
+	}
+
+	return audio
+}
+
+func (wl *WADLoader) GetPCSpeakerSounds() ([]PCSpeakerSound, bool) {
+	if len(wl.WADLumps) < 1 {
+		fmt.Println("[Warn] GetPCSpeakerSounds: No Lumps detected loaded, cannot detect PC speaker sounds!")
+		return nil, true
+	}
+
+	var sounds []PCSpeakerSound
+
+	for _, lump := range wl.WADLumps {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+		if !strings.HasPrefix(lumpName, "DP") || lump.LumpSize < 4 || !wl.IsLumpInBounds(lump) {
+			continue
+		}
+
+		lumpData, err := wl.ReadLumpData(lump)
+		if err != nil {
+			fmt.Println("[Warn] GetPCSpeakerSounds: Cannot read " + lumpName + ", omitting this lump.")
+			continue
+		}
+
+		sound, err := ParsePCSpeakerSound(lumpName, lumpData)
+		if err != nil {
+			fmt.Println("[Warn] GetPCSpeakerSounds: " + err.Error() + ", omitting this lump.")
+			continue
+		}
+
+		sounds = append(sounds, sound)
+	}
+
+	return sounds, false
+}
+
+func (wl *WADLoader) ExportAllPCSpeakerSounds(folderName string) error {
+	if len(wl.PCSounds) < 1 {
+		return errors.New("[Error] ExportAllPCSpeakerSounds: No PC speaker sounds inside WAD Loader")
+	}
+
+	folderName, err := createFolder(folderName)
+	if err != nil {
+		return errors.New("[Error] ExportAllPCSpeakerSounds: Cannot create the target folder - " + err.Error())
+	}
+
+	return runWorkers(len(wl.PCSounds), wl.workerCount(), func(idx int) error {
+		sound := wl.PCSounds[idx]
+		audio := sound.Synthesize(PCSpeakerSampleRate)
+		return audio.WriteFile(folderName + "/" + sound.name + ".wav")
+	})
+}
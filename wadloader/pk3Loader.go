@@ -0,0 +1,211 @@
+package wadloader
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// PK3 folders that map onto WAD namespaces, the rest of the files are global lumps
+var pk3NamespaceFolders = map[string]string{
+	"sprites":   "S",
+	"flats":     "F",
+	"patches":   "P",
+	"textures":  "TX",
+	"colormaps": "C",
+	"acs":       "A",
+	"voices":    "V",
+	"hires":     "HI",
+	"voxels":    "VX",
+	"music":     "MS",
+}
+
+// namespaces are written in this order, like the IWADs do
+var pk3NamespaceOrder = []string{"sprites", "patches", "flats", "textures", "colormaps", "acs", "voices", "hires", "voxels", "music"}
+
+// a PK3 is loaded as an in memory PWAD where each folder namespace is wrapped with its markers
+func (wl *WADLoader) OpenAndLoadPK3(pk3Filename string) error {
+	wl.WADFilename = pk3Filename
+
+	pk3File, err := os.Open(pk3Filename)
+	if err != nil {
+		return errors.New("[Error] OpenAndLoadPK3: Couldn't open the PK3 file - " + err.Error())
+	}
+
+	defer pk3File.Close()
+
+	fileInfo, err := pk3File.Stat()
+	if err != nil {
+		return errors.New("[Error] OpenAndLoadPK3: Couldn't get the PK3 file size - " + err.Error())
+	}
+
+	return wl.LoadPK3FromReaderAt(pk3File, fileInfo.Size())
+}
+
+func (wl *WADLoader) LoadPK3FromReaderAt(r io.ReaderAt, size int64) error {
+	zipReader, err := zip.NewReader(r, size)
+	if err != nil {
+		return errors.New("[Error] LoadPK3FromReaderAt: Cannot read the PK3 archive - " + err.Error())
+	}
+
+	var globalFiles []*zip.File
+	var mapFiles []*zip.File
+	namespaceFiles := make(map[string][]*zip.File)
+
+	for _, zipFile := range zipReader.File {
+		if zipFile.FileInfo().IsDir() {
+			continue
+		}
+
+		filePath := strings.Trim(zipFile.Name, "/")
+		topFolder := ""
+		if slashIdx := strings.Index(filePath, "/"); slashIdx != -1 {
+			topFolder = strings.ToLower(filePath[:slashIdx])
+		}
+
+		switch {
+		case topFolder == "maps" && strings.EqualFold(path.Ext(filePath), ".wad"):
+			mapFiles = append(mapFiles, zipFile)
+		case pk3NamespaceFolders[topFolder] != "":
+			namespaceFiles[topFolder] = append(namespaceFiles[topFolder], zipFile)
+		default:
+			globalFiles = append(globalFiles, zipFile)
+		}
+	}
+
+	wadWriter := WADWriter{WadType: "PWAD"}
+
+	for _, zipFile := range globalFiles {
+		err := addPK3FileLump(&wadWriter, zipFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, folder := range pk3NamespaceOrder {
+		files := namespaceFiles[folder]
+		if len(files) < 1 {
+			continue
+		}
+
+		sort.SliceStable(files, func(i, j int) bool {
+			return strings.ToUpper(files[i].Name) < strings.ToUpper(files[j].Name)
+		})
+
+		prefix := pk3NamespaceFolders[folder]
+		wadWriter.AddMarker(prefix + "_START")
+
+		for _, zipFile := range files {
+			err := addPK3FileLump(&wadWriter, zipFile)
+			if err != nil {
+				return err
+			}
+		}
+
+		wadWriter.AddMarker(prefix + "_END")
+	}
+
+	for _, zipFile := range mapFiles {
+		err := addPK3EmbeddedWAD(&wadWriter, zipFile)
+		if err != nil {
+			fmt.Println("[Warn] LoadPK3FromReaderAt: Cannot open embedded map WAD (skipping):", err.Error())
+		}
+	}
+
+	wl.WADBuffer = wadWriter.Bytes()
+
+	err = wl.LoadFromReaderAt(bytes.NewReader(wl.WADBuffer), int64(len(wl.WADBuffer)))
+	if err != nil {
+		return err
+	}
+
+	wl.WADHeader.WadType = [4]byte{'P', 'K', '3', 0}
+
+	return nil
+}
+
+func readPK3File(zipFile *zip.File) ([]byte, error) {
+	fileReader, err := zipFile.Open()
+	if err != nil {
+		return nil, errors.New("[Error] readPK3File: Cannot open " + zipFile.Name + " - " + err.Error())
+	}
+
+	defer fileReader.Close()
+
+	data, err := io.ReadAll(fileReader)
+	if err != nil {
+		return nil, errors.New("[Error] readPK3File: Cannot read " + zipFile.Name + " - " + err.Error())
+	}
+
+	return data, nil
+}
+
+func addPK3FileLump(wadWriter *WADWriter, zipFile *zip.File) error {
+	data, err := readPK3File(zipFile)
+	if err != nil {
+		return err
+	}
+
+	wadWriter.AddLump(PK3FileLumpName(zipFile.Name), data)
+
+	return nil
+}
+
+// embedded map WADs are appended lump by lump so the maps can be detected as usual
+func addPK3EmbeddedWAD(wadWriter *WADWriter, zipFile *zip.File) error {
+	data, err := readPK3File(zipFile)
+	if err != nil {
+		return err
+	}
+
+	var embedded WADLoader
+
+	err = embedded.LoadFromReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+
+	wadType := string(embedded.WADHeader.WadType[:])
+	if wadType != "PWAD" && wadType != "IWAD" {
+		return errors.New("[Error] addPK3EmbeddedWAD: " + zipFile.Name + " is not a WAD file")
+	}
+
+	dirEnd := int64(embedded.WADHeader.LumpDirectoryOffset) + int64(embedded.WADHeader.LumpEntries)*16
+	if embedded.WADHeader.LumpEntries < 1 || dirEnd > embedded.WADSize {
+		return errors.New("[Error] addPK3EmbeddedWAD: " + zipFile.Name + " has an invalid lump directory")
+	}
+
+	embedded.ReadWADLumps()
+
+	for _, lump := range embedded.WADLumps {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+		lumpData, err := embedded.ReadLumpData(lump)
+		if err != nil {
+			return err
+		}
+
+		wadWriter.AddLump(lumpName, lumpData)
+	}
+
+	return nil
+}
+
+// "sprites/TROOA1.png" -> "TROOA1", '^' stands for '\' as PK3 file names can't contain it
+func PK3FileLumpName(filePath string) string {
+	baseName := path.Base(filePath)
+	baseName = strings.TrimSuffix(baseName, path.Ext(baseName))
+	baseName = strings.ReplaceAll(baseName, "^", "\\")
+
+	if len(baseName) > 8 {
+		baseName = baseName[:8]
+	}
+
+	return strings.ToUpper(baseName)
+}
@@ -0,0 +1,154 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/png"
+	"strconv"
+)
+
+const (
+	pngSignature = "\x89PNG\r\n\x1a\n"
+	// post top offsets are a byte and 255 ends the column
+	pngPatchMaxHeight = 254
+	// pixels with a lower alpha are left out of the posts
+	pngOpaqueAlpha = 128
+)
+
+func isPNGData(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(pngSignature))
+}
+
+// size from the IHDR chunk, right after the signature
+func pngSize(header []byte) (int, int, bool) {
+	if len(header) < 24 || !isPNGData(header) || string(header[12:16]) != "IHDR" {
+		return 0, 0, false
+	}
+
+	return int(binary.BigEndian.Uint32(header[16:20])), int(binary.BigEndian.Uint32(header[20:24])), true
+}
+
+// the grAb chunk written by SLADE and GZDoom holds the graphic offsets
+func pngGrabOffsets(data []byte) (int16, int16) {
+	for offset := len(pngSignature); offset+8 <= len(data); {
+		chunkLength := int(binary.BigEndian.Uint32(data[offset : offset+4]))
+		chunkType := string(data[offset+4 : offset+8])
+
+		if chunkLength < 0 || offset+8+chunkLength > len(data) || chunkType == "IDAT" || chunkType == "IEND" {
+			break
+		}
+
+		if chunkType == "grAb" && chunkLength >= 8 {
+			chunkData := data[offset+8:]
+			return int16(int32(binary.BigEndian.Uint32(chunkData[0:4]))), int16(int32(binary.BigEndian.Uint32(chunkData[4:8])))
+		}
+
+		// length, type, data and crc
+		offset += 12 + chunkLength
+	}
+
+	return 0, 0
+}
+
+func pngPatchSizeError(width int, height int) error {
+	if width > 0xFFFF || height > pngPatchMaxHeight {
+		return errors.New("is " + strconv.Itoa(width) + "x" + strconv.Itoa(height) + ", patches are up to " + strconv.Itoa(pngPatchMaxHeight) + " pixels tall")
+	}
+	return nil
+}
+
+func pngFlatSizeError(width int, height int) error {
+	if width < 64 || height < 64 {
+		return errors.New("is " + strconv.Itoa(width) + "x" + strconv.Itoa(height) + ", flats are at least 64x64")
+	}
+	return nil
+}
+
+func decodePNGGraphic(lumpName string, data []byte) (image.Image, error) {
+	img, err := png.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, errors.New("[Error] decodePNGGraphic: Cannot decode " + lumpName + " - " + err.Error())
+	}
+	return img, nil
+}
+
+// the colors are matched against the palette, transparent pixels become gaps between posts
+func PNGToPatch(lumpName string, data []byte, palette Palette) (Patch, error) {
+	patch := Patch{Name: lumpName}
+
+	img, err := decodePNGGraphic(lumpName, data)
+	if err != nil {
+		return patch, err
+	}
+
+	bounds := img.Bounds()
+	if err := pngPatchSizeError(bounds.Dx(), bounds.Dy()); err != nil {
+		return patch, errors.New("[Error] PNGToPatch: " + lumpName + " " + err.Error())
+	}
+
+	patch.Width = uint16(bounds.Dx())
+	patch.Height = uint16(bounds.Dy())
+	patch.LeftOffset, patch.TopOffset = pngGrabOffsets(data)
+
+	// offsets as a patch lump would lay the columns out
+	postOffset := uint32(8 + 4*bounds.Dx())
+
+	for x := bounds.Min.X; x < bounds.Max.X; x++ {
+		var post PatchPost
+		var segment *PatchPostSegment
+
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			pixel := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if pixel.A < pngOpaqueAlpha {
+				segment = nil
+				continue
+			}
+
+			if segment == nil {
+				post = append(post, PatchPostSegment{TopOffset: uint8(y - bounds.Min.Y)})
+				segment = &post[len(post)-1]
+			}
+
+			segment.PixelData = append(segment.PixelData, NearestPaletteColor(palette, int(pixel.R), int(pixel.G), int(pixel.B)))
+			segment.Length++
+		}
+
+		patch.PostOffsets = append(patch.PostOffsets, postOffset)
+		patch.PatchPosts = append(patch.PatchPosts, post)
+
+		// each segment has 4 bytes around its pixels, the column ends with 255
+		for _, postSegment := range post {
+			postOffset += uint32(len(postSegment.PixelData)) + 4
+		}
+		postOffset++
+	}
+
+	return patch, nil
+}
+
+// like the lump flats, only the first 64x64 block of bigger PNG's is read
+func PNGToFlat(lumpName string, data []byte, palette Palette) (Flat, error) {
+	flat := Flat{Name: lumpName}
+
+	img, err := decodePNGGraphic(lumpName, data)
+	if err != nil {
+		return flat, err
+	}
+
+	bounds := img.Bounds()
+	if err := pngFlatSizeError(bounds.Dx(), bounds.Dy()); err != nil {
+		return flat, errors.New("[Error] PNGToFlat: " + lumpName + " " + err.Error())
+	}
+
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			pixel := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y)).(color.NRGBA)
+			flat.PixelData[x+y*64] = NearestPaletteColor(palette, int(pixel.R), int(pixel.G), int(pixel.B))
+		}
+	}
+
+	return flat, nil
+}
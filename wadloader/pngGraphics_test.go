@@ -0,0 +1,80 @@
+package wadloader
+
+import (
+	"image"
+	"image/color"
+	"testing"
+)
+
+func grayPalette() Palette {
+	var palette Palette
+	for idx := range palette {
+		palette[idx] = PaletteColor{uint8(idx), uint8(idx), uint8(idx)}
+	}
+	return palette
+}
+
+// a 3x6 sprite with rows 2 and 3 transparent, written with offsets like -convert-png does
+func TestPNGToPatch(t *testing.T) {
+	img := image.NewNRGBA(image.Rect(0, 0, 3, 6))
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 3; x++ {
+			if y == 2 || y == 3 {
+				continue
+			}
+			img.Set(x, y, color.NRGBA{uint8(x*10 + y), uint8(x*10 + y), uint8(x*10 + y), 255})
+		}
+	}
+
+	data, err := encodePNGWithOffsets(img, 7, -3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patch, err := PNGToPatch("TROOA1", data, grayPalette())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if patch.Width != 3 || patch.Height != 6 || patch.LeftOffset != 7 || patch.TopOffset != -3 {
+		t.Fatalf("got %dx%d with offsets %d,%d, want 3x6 with offsets 7,-3", patch.Width, patch.Height, patch.LeftOffset, patch.TopOffset)
+	}
+
+	for x, post := range patch.PatchPosts {
+		if len(post) != 2 {
+			t.Fatalf("column %d has %d segments, want 2", x, len(post))
+		}
+
+		for idx, wantTop := range []uint8{0, 4} {
+			segment := post[idx]
+			if segment.TopOffset != wantTop || segment.Length != 2 {
+				t.Errorf("column %d segment %d starts at %d with %d pixels, want %d and 2", x, idx, segment.TopOffset, segment.Length, wantTop)
+				continue
+			}
+			for i, pixel := range segment.PixelData {
+				if want := uint8(x*10 + int(wantTop) + i); pixel != want {
+					t.Errorf("column %d row %d is color %d, want %d", x, int(wantTop)+i, pixel, want)
+				}
+			}
+		}
+	}
+
+	// the patch renders back to the same picture
+	rendered := PatchToImage(patch, grayPalette())
+	for y := 0; y < 6; y++ {
+		for x := 0; x < 3; x++ {
+			if got, want := color.NRGBAModel.Convert(rendered.At(x, y)), img.At(x, y); got != want {
+				t.Errorf("pixel %d,%d is %v, want %v", x, y, got, want)
+			}
+		}
+	}
+}
+
+func TestPNGSizeLimits(t *testing.T) {
+	if pngPatchSizeError(320, pngPatchMaxHeight) != nil || pngPatchSizeError(16, pngPatchMaxHeight+1) == nil {
+		t.Error("patches must be accepted up to 254 pixels tall")
+	}
+	if pngFlatSizeError(64, 64) != nil || pngFlatSizeError(128, 128) != nil || pngFlatSizeError(32, 64) == nil {
+		t.Error("flats must be accepted from 64x64")
+	}
+}
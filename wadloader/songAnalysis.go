@@ -0,0 +1,181 @@
+package wadloader
+
+import (
+	"fmt"
+	"sort"
+)
+
+const (
+	// silence at the start or the end of a song that is heard as a gap when it loops
+	songLoopLeadingSilence  = 0.5
+	songLoopTrailingSilence = 2.0
+)
+
+type SongTempoChange struct {
+	Time float64
+	BPM  float64
+}
+
+type SongAnalysis struct {
+	Name     string
+	Format   string
+	Duration float64
+	// MIDI tempo events, MUS songs play at a fixed 140 Hz
+	TempoChanges []SongTempoChange
+	// channels playing notes, percussion is channel 9
+	Channels []int
+	// programs used by the melodic channels, channels without a program change play program 0
+	Programs        map[int][]int
+	NoteCount       int
+	PercussionNotes int
+	// drum notes played on the percussion channel
+	PercussionKeys []int
+	// reasons why the song doesn't loop cleanly, empty when it does
+	LoopProblems []string
+	// malformed data found while parsing
+	Problems []string
+}
+
+func (sa *SongAnalysis) Loopable() bool {
+	return len(sa.LoopProblems) == 0
+}
+
+func sortedKeys(set map[int]bool) []int {
+	keys := make([]int, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	return keys
+}
+
+func AnalyzeSong(name string, song Song) SongAnalysis {
+	analysis := SongAnalysis{
+		Name:     name,
+		Format:   song.Format,
+		Duration: song.Duration,
+		Programs: make(map[int][]int),
+		Problems: song.Problems,
+	}
+
+	var channelPrograms [16]int
+	channels := make(map[int]bool)
+	programs := make(map[int]map[int]bool)
+	percussionKeys := make(map[int]bool)
+	// notes still sounding, by channel and key
+	heldNotes := make(map[[2]int]bool)
+
+	firstNote, lastNoteEnd := -1.0, 0.0
+	tempoAtStart := false
+
+	for _, event := range song.Events {
+		channel := event.Channel & 0x0F
+
+		switch event.Kind {
+		case SongNoteOn:
+			analysis.NoteCount++
+			channels[channel] = true
+			heldNotes[[2]int{channel, event.Data1}] = true
+
+			if firstNote < 0 {
+				firstNote = event.Time
+			}
+
+			if channel == MIDIPercussionChannel {
+				analysis.PercussionNotes++
+				percussionKeys[event.Data1] = true
+				continue
+			}
+
+			if programs[channel] == nil {
+				programs[channel] = make(map[int]bool)
+			}
+			programs[channel][channelPrograms[channel]] = true
+		case SongNoteOff:
+			if heldNotes[[2]int{channel, event.Data1}] {
+				delete(heldNotes, [2]int{channel, event.Data1})
+				lastNoteEnd = event.Time
+			}
+		case SongProgram:
+			channelPrograms[channel] = event.Data1
+		case SongController:
+			if event.Data1 == midiControllerAllNotesOff || event.Data1 == midiControllerAllSoundsOff {
+				for note := range heldNotes {
+					if note[0] == channel {
+						delete(heldNotes, note)
+						lastNoteEnd = event.Time
+					}
+				}
+			}
+		case SongTempo:
+			if event.Data1 <= 0 {
+				continue
+			}
+			if event.Tick == 0 {
+				tempoAtStart = true
+			}
+			analysis.TempoChanges = append(analysis.TempoChanges, SongTempoChange{Time: event.Time, BPM: 60000000 / float64(event.Data1)})
+		}
+	}
+
+	analysis.Channels = sortedKeys(channels)
+	analysis.PercussionKeys = sortedKeys(percussionKeys)
+	for channel, used := range programs {
+		analysis.Programs[channel] = sortedKeys(used)
+	}
+
+	if analysis.NoteCount == 0 {
+		analysis.LoopProblems = append(analysis.LoopProblems, "the song plays no notes")
+		return analysis
+	}
+
+	if len(heldNotes) > 0 {
+		analysis.LoopProblems = append(analysis.LoopProblems, fmt.Sprintf("%d notes are still playing when the song ends", len(heldNotes)))
+		lastNoteEnd = song.Duration
+	}
+
+	if firstNote > songLoopLeadingSilence {
+		analysis.LoopProblems = append(analysis.LoopProblems, fmt.Sprintf("%.2f seconds of silence before the first note", firstNote))
+	}
+
+	if song.Duration-lastNoteEnd > songLoopTrailingSilence {
+		analysis.LoopProblems = append(analysis.LoopProblems, fmt.Sprintf("%.2f seconds of silence after the last note", song.Duration-lastNoteEnd))
+	}
+
+	// players keep the last tempo when looping unless the song sets it again at its start
+	if len(analysis.TempoChanges) > 0 && !tempoAtStart {
+		lastBPM := analysis.TempoChanges[len(analysis.TempoChanges)-1].BPM
+		startBPM := 60000000 / float64(midiDefaultTempo)
+
+		if lastBPM != startBPM {
+			analysis.LoopProblems = append(analysis.LoopProblems, fmt.Sprintf("the song ends at %.1f BPM without a tempo event at its start to restore %.1f BPM", lastBPM, startBPM))
+		}
+	}
+
+	return analysis
+}
+
+// songs that cannot be parsed are reported with the error as their only problem
+func (wl *WADLoader) AnalyzeSongs() []SongAnalysis {
+	analyses := make([]SongAnalysis, len(wl.Music))
+
+	for idx, musicLump := range wl.Music {
+		analyses[idx] = SongAnalysis{Name: musicLump.name, Format: musicLump.format}
+
+		lumpData, err := wl.ReadLumpData(musicLump.lump)
+		if err != nil {
+			analyses[idx].Problems = []string{err.Error()}
+			continue
+		}
+
+		song, err := ParseSong(musicLump.format, lumpData)
+		if err != nil {
+			analyses[idx].Problems = []string{err.Error()}
+			continue
+		}
+
+		analyses[idx] = AnalyzeSong(musicLump.name, song)
+	}
+
+	return analyses
+}
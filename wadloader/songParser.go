@@ -0,0 +1,459 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// MUS and MIDI songs are turned into the same list of MIDI style events
+type SongEventKind int
+
+const (
+	SongNoteOff SongEventKind = iota
+	SongNoteOn
+	SongProgram
+	SongController
+	SongPitchBend
+	SongTempo
+	SongEnd
+)
+
+const (
+	MIDIPercussionChannel = 9
+
+	musTickRate = 140
+	// 120 BPM until the first tempo event
+	midiDefaultTempo = 500000
+)
+
+const (
+	midiControllerVolume       = 7
+	midiControllerPan          = 10
+	midiControllerAllSoundsOff = 120
+	midiControllerResetAll     = 121
+	midiControllerAllNotesOff  = 123
+	midiPitchBendCenter        = 8192
+)
+
+// MUS event types, the controller 0 is the program change
+const (
+	musReleaseNote = iota
+	musPlayNote
+	musPitchBend
+	musSystemEvent
+	musController
+	musEndOfMeasure
+	musScoreEnd
+
+	musDefaultNoteVolume     = 127
+	musPercussionChannel     = 15
+	musControllerProgram     = 0
+	musFirstSystemController = 10
+)
+
+// MUS controllers 1-9 and system events 10-14 as MIDI controllers
+var musToMIDIControllers = [15]int{0, 0, 1, 7, 10, 11, 91, 93, 64, 67, 120, 123, 126, 127, 121}
+
+type SongEvent struct {
+	Tick    int
+	Time    float64
+	Channel int
+	Kind    SongEventKind
+	// note and velocity, program, controller and value, 14 bits pitch bend or tempo in microseconds per quarter
+	Data1 int
+	Data2 int
+}
+
+type Song struct {
+	Format string
+	Events []SongEvent
+	// seconds until the last event
+	Duration float64
+	// issues found while parsing, the song is still played as far as it could be read
+	Problems []string
+}
+
+type musHeader struct {
+	ID                [4]byte
+	ScoreLength       uint16
+	ScoreStart        uint16
+	PrimaryChannels   uint16
+	SecondaryChannels uint16
+	InstrumentCount   uint16
+	Reserved          uint16
+}
+
+func ParseSong(format string, data []byte) (Song, error) {
+	switch format {
+	case "MUS":
+		return ParseMUS(data)
+	case "MIDI":
+		return ParseMIDI(data)
+	case "RMI":
+		song, err := ParseMIDI(rmiMIDIData(data))
+		song.Format = format
+		return song, err
+	}
+
+	return Song{Format: format}, errors.New("[Error] ParseSong: Only MUS, MIDI and RMI songs can be parsed, got " + format)
+}
+
+// RMI files are a RIFF RMID container with the MIDI file as the "data" chunk
+func rmiMIDIData(data []byte) []byte {
+	pos := 12
+	for pos+8 <= len(data) {
+		chunkLength := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		if string(data[pos:pos+4]) == "data" {
+			end := pos + 8 + chunkLength
+			if end > len(data) || end < pos {
+				end = len(data)
+			}
+			return data[pos+8 : end]
+		}
+		// chunks are padded to an even length
+		pos += 8 + chunkLength + chunkLength&1
+	}
+
+	return nil
+}
+
+// MUS channel 15 is percussion, it goes to the MIDI channel 9 and channels 9-14 move up one
+func musToMIDIChannel(channel int) int {
+	if channel == musPercussionChannel {
+		return MIDIPercussionChannel
+	}
+	if channel >= MIDIPercussionChannel {
+		return channel + 1
+	}
+	return channel
+}
+
+func ParseMUS(data []byte) (Song, error) {
+	song := Song{Format: "MUS"}
+
+	var header musHeader
+	err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &header)
+	if err != nil || string(header.ID[:]) != "MUS\x1a" {
+		return song, errors.New("[Error] ParseMUS: Missing the MUS header")
+	}
+
+	scoreStart := int(header.ScoreStart)
+	scoreEnd := scoreStart + int(header.ScoreLength)
+	if scoreStart > len(data) {
+		return song, errors.New("[Error] ParseMUS: The score starts past the end of the lump")
+	}
+	if scoreEnd > len(data) {
+		song.Problems = append(song.Problems, "the declared score length goes past the end of the lump")
+	}
+
+	var noteVolumes [16]int
+	for idx := range noteVolumes {
+		noteVolumes[idx] = musDefaultNoteVolume
+	}
+
+	pos, tick, ended := scoreStart, 0, false
+
+	readByte := func() (int, bool) {
+		if pos >= len(data) {
+			return 0, false
+		}
+		value := int(data[pos])
+		pos++
+		return value, true
+	}
+
+	// the declared length isn't trusted, events are read until the score end event
+	for !ended {
+		if pos >= len(data) {
+			song.Problems = append(song.Problems, "the score ends without a score end event")
+			break
+		}
+
+		descriptor, _ := readByte()
+		last := descriptor&0x80 != 0
+		eventType := (descriptor >> 4) & 0x07
+		channel := musToMIDIChannel(descriptor & 0x0F)
+
+		event := SongEvent{Tick: tick, Channel: channel}
+		valid := true
+
+		switch eventType {
+		case musReleaseNote:
+			note, ok := readByte()
+			event.Kind, event.Data1, valid = SongNoteOff, note&0x7F, ok
+		case musPlayNote:
+			note, ok := readByte()
+			if ok && note&0x80 != 0 {
+				noteVolumes[channel], ok = readByte()
+				noteVolumes[channel] &= 0x7F
+			}
+			event.Kind, event.Data1, event.Data2, valid = SongNoteOn, note&0x7F, noteVolumes[channel], ok
+		case musPitchBend:
+			bend, ok := readByte()
+			event.Kind, event.Data1, valid = SongPitchBend, bend*64, ok
+		case musSystemEvent:
+			controller, ok := readByte()
+			if controller < musFirstSystemController || controller >= len(musToMIDIControllers) {
+				song.Problems = append(song.Problems, fmt.Sprintf("unknown system event %d", controller))
+				ok = false
+			} else {
+				event.Kind, event.Data1 = SongController, musToMIDIControllers[controller]
+			}
+			valid = ok
+		case musController:
+			controller, ok := readByte()
+			value, okValue := readByte()
+			valid = ok && okValue
+
+			if controller == musControllerProgram {
+				event.Kind, event.Data1 = SongProgram, value&0x7F
+			} else if controller < musFirstSystemController {
+				event.Kind, event.Data1, event.Data2 = SongController, musToMIDIControllers[controller], value&0x7F
+			} else {
+				song.Problems = append(song.Problems, fmt.Sprintf("unknown controller %d", controller))
+				valid = false
+			}
+		case musScoreEnd:
+			event.Kind, ended = SongEnd, true
+		default:
+			// end of measure and the unused type carry no data
+			valid = false
+		}
+
+		if valid || event.Kind == SongEnd {
+			song.Events = append(song.Events, event)
+		}
+
+		if last {
+			delay := 0
+			for {
+				value, ok := readByte()
+				if !ok {
+					break
+				}
+				delay = delay<<7 | value&0x7F
+				if value&0x80 == 0 {
+					break
+				}
+			}
+			tick += delay
+		}
+	}
+
+	if pos > scoreEnd {
+		song.Problems = append(song.Problems, fmt.Sprintf("%d bytes of events past the declared score length", pos-scoreEnd))
+	} else if ended && pos < scoreEnd {
+		song.Problems = append(song.Problems, fmt.Sprintf("%d bytes after the score end event", scoreEnd-pos))
+	}
+
+	for idx := range song.Events {
+		song.Events[idx].Time = float64(song.Events[idx].Tick) / musTickRate
+	}
+	song.Duration = float64(tick) / musTickRate
+
+	return song, nil
+}
+
+type midiHeader struct {
+	ID         [4]byte
+	Length     uint32
+	Format     uint16
+	TrackCount uint16
+	Division   uint16
+}
+
+// format 0 and 1 files are played merging every track, format 2 tracks are played one after the other
+func ParseMIDI(data []byte) (Song, error) {
+	song := Song{Format: "MIDI"}
+
+	var header midiHeader
+	err := binary.Read(bytes.NewReader(data), binary.BigEndian, &header)
+	if err != nil || string(header.ID[:]) != "MThd" {
+		return song, errors.New("[Error] ParseMIDI: Missing the MThd header")
+	}
+
+	pos := 8 + int(header.Length)
+	trackTickOffset := 0
+
+	for track := 0; track < int(header.TrackCount); track++ {
+		if pos+8 > len(data) {
+			song.Problems = append(song.Problems, fmt.Sprintf("track %d is missing, the file ends early", track))
+			break
+		}
+
+		chunkID := string(data[pos : pos+4])
+		chunkLength := int(binary.BigEndian.Uint32(data[pos+4:]))
+		pos += 8
+
+		if pos+chunkLength > len(data) {
+			song.Problems = append(song.Problems, fmt.Sprintf("track %d goes past the end of the file", track))
+			chunkLength = len(data) - pos
+		}
+
+		if chunkID != "MTrk" {
+			pos += chunkLength
+			track--
+			continue
+		}
+
+		events, lastTick, problems := parseMIDITrack(data[pos:pos+chunkLength], track, trackTickOffset)
+		song.Events = append(song.Events, events...)
+		song.Problems = append(song.Problems, problems...)
+
+		if header.Format == 2 {
+			trackTickOffset = lastTick
+		}
+
+		pos += chunkLength
+	}
+
+	sort.SliceStable(song.Events, func(i, j int) bool { return song.Events[i].Tick < song.Events[j].Tick })
+
+	// ticks to seconds following the tempo changes, SMPTE divisions use fixed frames per second
+	tempo, lastTick, lastTime := midiDefaultTempo, 0, 0.0
+	ticksPerSecond := func() float64 {
+		if header.Division&0x8000 != 0 {
+			framesPerSecond := float64(-int8(header.Division >> 8))
+			return framesPerSecond * float64(header.Division&0xFF)
+		}
+		return float64(header.Division) * 1000000 / float64(tempo)
+	}
+
+	if header.Division == 0 {
+		return song, errors.New("[Error] ParseMIDI: The division is 0")
+	}
+
+	for idx := range song.Events {
+		event := &song.Events[idx]
+		lastTime += float64(event.Tick-lastTick) / ticksPerSecond()
+		lastTick = event.Tick
+		event.Time = lastTime
+
+		if event.Kind == SongTempo && event.Data1 > 0 {
+			tempo = event.Data1
+		}
+	}
+
+	song.Duration = lastTime
+
+	return song, nil
+}
+
+func parseMIDITrack(data []byte, track int, tickOffset int) ([]SongEvent, int, []string) {
+	var events []SongEvent
+	var problems []string
+
+	pos, tick, runningStatus := 0, tickOffset, 0
+	endOfTrack := false
+
+	readVarLen := func() int {
+		value := 0
+		for pos < len(data) {
+			b := data[pos]
+			pos++
+			value = value<<7 | int(b&0x7F)
+			if b&0x80 == 0 {
+				break
+			}
+		}
+		return value
+	}
+
+	for pos < len(data) && !endOfTrack {
+		tick += readVarLen()
+		if pos >= len(data) {
+			break
+		}
+
+		status := int(data[pos])
+		if status&0x80 != 0 {
+			pos++
+			if status < 0xF0 {
+				runningStatus = status
+			}
+		} else if runningStatus != 0 {
+			status = runningStatus
+		} else {
+			problems = append(problems, fmt.Sprintf("track %d has data without a status byte", track))
+			break
+		}
+
+		switch {
+		case status == 0xFF:
+			if pos >= len(data) {
+				break
+			}
+			metaType := data[pos]
+			pos++
+			length := readVarLen()
+			if pos+length > len(data) {
+				pos = len(data)
+				break
+			}
+
+			if metaType == 0x51 && length == 3 {
+				tempo := int(data[pos])<<16 | int(data[pos+1])<<8 | int(data[pos+2])
+				events = append(events, SongEvent{Tick: tick, Kind: SongTempo, Data1: tempo})
+			} else if metaType == 0x2F {
+				events = append(events, SongEvent{Tick: tick, Kind: SongEnd})
+				endOfTrack = true
+			}
+			pos += length
+		case status == 0xF0 || status == 0xF7:
+			pos += readVarLen()
+		case status >= 0xF0:
+			// system common and real time messages have no place in a file, stop reading the track
+			problems = append(problems, fmt.Sprintf("track %d has an unexpected status 0x%X", track, status))
+			pos = len(data)
+		default:
+			channel := status & 0x0F
+			dataLength := 2
+			if status&0xF0 == 0xC0 || status&0xF0 == 0xD0 {
+				dataLength = 1
+			}
+			if pos+dataLength > len(data) {
+				pos = len(data)
+				break
+			}
+
+			data1 := int(data[pos] & 0x7F)
+			data2 := 0
+			if dataLength == 2 {
+				data2 = int(data[pos+1] & 0x7F)
+			}
+			pos += dataLength
+
+			event := SongEvent{Tick: tick, Channel: channel, Data1: data1, Data2: data2}
+
+			switch status & 0xF0 {
+			case 0x80:
+				event.Kind = SongNoteOff
+			case 0x90:
+				event.Kind = SongNoteOn
+				if data2 == 0 {
+					event.Kind = SongNoteOff
+				}
+			case 0xB0:
+				event.Kind = SongController
+			case 0xC0:
+				event.Kind = SongProgram
+			case 0xE0:
+				event.Kind, event.Data1 = SongPitchBend, data2<<7|data1
+			default:
+				// key and channel pressure are ignored
+				continue
+			}
+
+			events = append(events, event)
+		}
+	}
+
+	if !endOfTrack {
+		problems = append(problems, fmt.Sprintf("track %d has no end of track event", track))
+	}
+
+	return events, tick, problems
+}
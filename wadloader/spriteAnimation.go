@@ -0,0 +1,308 @@
+package wadloader
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"image/png"
+	"os"
+	"sort"
+	"strconv"
+)
+
+// 4 tics at 35 tics per second, in GIF hundredths of a second
+const spriteAnimationDelay = 11
+
+// sprite lumps are named NAME + frame letter + rotation digit, optionally
+// followed by a second frame/rotation pair drawn mirrored (TROOA2A8)
+type SpriteSet struct {
+	Name   string
+	Frames []SpriteFrame
+}
+
+type SpriteFrame struct {
+	Letter byte
+	// index 0 is used for every angle, 1-8 go clockwise from the front
+	Rotations [9]*SpriteRotation
+}
+
+type SpriteRotation struct {
+	Patch    *Patch
+	Mirrored bool
+}
+
+func GroupSprites(sprites []Patch) []SpriteSet {
+	setsByName := make(map[string]*SpriteSet)
+	var setNames []string
+
+	for idx := range sprites {
+		sprite := &sprites[idx]
+		name := sprite.Name
+
+		if len(name) != 6 && len(name) != 8 {
+			fmt.Println("[Warn] GroupSprites: " + name + " doesn't follow the sprite naming scheme, skipping")
+			continue
+		}
+
+		set, found := setsByName[name[:4]]
+		if !found {
+			set = &SpriteSet{Name: name[:4]}
+			setsByName[name[:4]] = set
+			setNames = append(setNames, name[:4])
+		}
+
+		set.addRotation(name[4], name[5], sprite, false)
+
+		if len(name) == 8 {
+			set.addRotation(name[6], name[7], sprite, true)
+		}
+	}
+
+	sort.Strings(setNames)
+
+	sets := make([]SpriteSet, 0, len(setNames))
+	for _, setName := range setNames {
+		set := setsByName[setName]
+		sort.Slice(set.Frames, func(i, j int) bool { return set.Frames[i].Letter < set.Frames[j].Letter })
+		sets = append(sets, *set)
+	}
+
+	return sets
+}
+
+func (ss *SpriteSet) addRotation(frameLetter byte, rotationDigit byte, sprite *Patch, mirrored bool) {
+	if frameLetter < 'A' || frameLetter > ']' || rotationDigit < '0' || rotationDigit > '8' {
+		fmt.Println("[Warn] GroupSprites: " + sprite.Name + " has an invalid frame or rotation, skipping")
+		return
+	}
+
+	var frame *SpriteFrame
+	for idx := range ss.Frames {
+		if ss.Frames[idx].Letter == frameLetter {
+			frame = &ss.Frames[idx]
+			break
+		}
+	}
+
+	if frame == nil {
+		ss.Frames = append(ss.Frames, SpriteFrame{Letter: frameLetter})
+		frame = &ss.Frames[len(ss.Frames)-1]
+	}
+
+	frame.Rotations[rotationDigit-'0'] = &SpriteRotation{Patch: sprite, Mirrored: mirrored}
+}
+
+// frames without the rotation use their all angles (0) image
+func (sf *SpriteFrame) Rotation(rotation int) *SpriteRotation {
+	if sf.Rotations[rotation] != nil {
+		return sf.Rotations[rotation]
+	}
+	return sf.Rotations[0]
+}
+
+// rotations used by any frame, 0 only when the sprite has no angled frames
+func (ss *SpriteSet) UsedRotations() []int {
+	var rotations []int
+
+	for rotation := 1; rotation <= 8; rotation++ {
+		for _, frame := range ss.Frames {
+			if frame.Rotations[rotation] != nil {
+				rotations = append(rotations, rotation)
+				break
+			}
+		}
+	}
+
+	if len(rotations) < 1 {
+		rotations = append(rotations, 0)
+	}
+
+	return rotations
+}
+
+// mirrored rotations are flipped, so their offset is measured from the other side
+func (sr *SpriteRotation) offsets() (int, int) {
+	leftOffset := int(sr.Patch.LeftOffset)
+	if sr.Mirrored {
+		leftOffset = int(sr.Patch.Width) - leftOffset
+	}
+	return leftOffset, int(sr.Patch.TopOffset)
+}
+
+// the box holding every frame with their offsets (origin) on the same point
+func (ss *SpriteSet) alignedBounds() image.Rectangle {
+	var bounds image.Rectangle
+
+	for _, frame := range ss.Frames {
+		for _, rotation := range frame.Rotations {
+			if rotation == nil {
+				continue
+			}
+
+			leftOffset, topOffset := rotation.offsets()
+			frameRect := image.Rect(-leftOffset, -topOffset, int(rotation.Patch.Width)-leftOffset, int(rotation.Patch.Height)-topOffset)
+			bounds = bounds.Union(frameRect)
+		}
+	}
+
+	return bounds
+}
+
+// calls drawPixel with the canvas position of each patch pixel
+func (sr *SpriteRotation) drawAligned(bounds image.Rectangle, drawPixel func(x int, y int, colorIdx uint8)) {
+	leftOffset, topOffset := sr.offsets()
+	originX := -leftOffset - bounds.Min.X
+	originY := -topOffset - bounds.Min.Y
+	width := int(sr.Patch.Width)
+
+	for column, post := range sr.Patch.PatchPosts {
+		x := column
+		if sr.Mirrored {
+			x = width - 1 - column
+		}
+
+		for _, postSegment := range post {
+			for i := 0; i < int(postSegment.Length); i++ {
+				drawPixel(originX+x, originY+int(postSegment.TopOffset)+i, postSegment.PixelData[i])
+			}
+		}
+	}
+}
+
+// GIF palettes need a free entry for transparency, so only the used colors are kept
+func (ss *SpriteSet) gifPalette(palette Palette) (color.Palette, map[uint8]uint8) {
+	gifPalette := color.Palette{color.RGBA{0, 0, 0, 0}}
+	colorMapping := make(map[uint8]uint8)
+
+	for _, frame := range ss.Frames {
+		for _, rotation := range frame.Rotations {
+			if rotation == nil {
+				continue
+			}
+
+			rotation.drawAligned(image.Rectangle{}, func(x int, y int, colorIdx uint8) {
+				if _, found := colorMapping[colorIdx]; found {
+					return
+				}
+
+				paletteColor := palette[colorIdx]
+				rgbaColor := color.RGBA{paletteColor.Red, paletteColor.Green, paletteColor.Blue, 255}
+
+				if len(gifPalette) < 256 {
+					colorMapping[colorIdx] = uint8(len(gifPalette))
+					gifPalette = append(gifPalette, rgbaColor)
+				} else {
+					colorMapping[colorIdx] = uint8(gifPalette[1:].Index(rgbaColor) + 1)
+				}
+			})
+		}
+	}
+
+	return gifPalette, colorMapping
+}
+
+func (ss *SpriteSet) ExportRotationGIF(rotation int, palette Palette, outputFolder string) error {
+	bounds := ss.alignedBounds()
+	gifPalette, colorMapping := ss.gifPalette(palette)
+
+	animation := gif.GIF{LoopCount: 0}
+
+	for _, frame := range ss.Frames {
+		frameRotation := frame.Rotation(rotation)
+		if frameRotation == nil {
+			continue
+		}
+
+		frameImg := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), gifPalette)
+		frameRotation.drawAligned(bounds, func(x int, y int, colorIdx uint8) {
+			frameImg.SetColorIndex(x, y, colorMapping[colorIdx])
+		})
+
+		animation.Image = append(animation.Image, frameImg)
+		animation.Delay = append(animation.Delay, spriteAnimationDelay)
+		animation.Disposal = append(animation.Disposal, gif.DisposalBackground)
+	}
+
+	if len(animation.Image) < 1 {
+		return errors.New("[Error] ExportRotationGIF: " + ss.Name + " has no frames for rotation " + strconv.Itoa(rotation))
+	}
+
+	gifFile, err := os.Create(outputFolder + "/" + ss.Name + "_rot" + strconv.Itoa(rotation) + ".gif")
+	if err != nil {
+		return errors.New("[Error] ExportRotationGIF: Cannot create the target file for the sprite - " + ss.Name + " - " + err.Error())
+	}
+
+	defer gifFile.Close()
+
+	return gif.EncodeAll(gifFile, &animation)
+}
+
+// one row per frame and one column per used rotation, every cell the size of the aligned bounds
+func (ss *SpriteSet) SheetImage(palette Palette) *image.RGBA {
+	bounds := ss.alignedBounds()
+	rotations := ss.UsedRotations()
+
+	sheetImg := image.NewRGBA(image.Rect(0, 0, bounds.Dx()*len(rotations), bounds.Dy()*len(ss.Frames)))
+	draw.Draw(sheetImg, sheetImg.Bounds(), image.Transparent, image.Point{}, draw.Src)
+
+	for row, frame := range ss.Frames {
+		for col, rotation := range rotations {
+			frameRotation := frame.Rotation(rotation)
+			if frameRotation == nil {
+				continue
+			}
+
+			cellX := col * bounds.Dx()
+			cellY := row * bounds.Dy()
+
+			frameRotation.drawAligned(bounds, func(x int, y int, colorIdx uint8) {
+				pixelColor := palette[colorIdx]
+				sheetImg.Set(cellX+x, cellY+y, color.RGBA{pixelColor.Red, pixelColor.Green, pixelColor.Blue, 255})
+			})
+		}
+	}
+
+	return sheetImg
+}
+
+func (ss *SpriteSet) ExportSheet(palette Palette, outputFolder string) error {
+	sheetFile, err := os.Create(outputFolder + "/" + ss.Name + "_sheet.png")
+	if err != nil {
+		return errors.New("[Error] ExportSheet: Cannot create the target file for the sprite - " + ss.Name + " - " + err.Error())
+	}
+
+	defer sheetFile.Close()
+
+	return png.Encode(sheetFile, ss.SheetImage(palette))
+}
+
+func (wl *WADLoader) ExportSpriteAnimations(outputFolder string) error {
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	palette := wl.renderPalette()
+	spriteSets := GroupSprites(wl.Sprites)
+
+	return runWorkers(len(spriteSets), wl.workerCount(), func(idx int) error {
+		spriteSet := spriteSets[idx]
+
+		for _, rotation := range spriteSet.UsedRotations() {
+			exportErr := spriteSet.ExportRotationGIF(rotation, palette, outputFolder)
+			if exportErr != nil {
+				return errors.New("[Error] ExportSpriteAnimations: Cannot export sprite - " + spriteSet.Name + " - " + exportErr.Error())
+			}
+		}
+
+		exportErr := spriteSet.ExportSheet(palette, outputFolder)
+		if exportErr != nil {
+			return errors.New("[Error] ExportSpriteAnimations: Cannot export sprite sheet - " + spriteSet.Name + " - " + exportErr.Error())
+		}
+
+		return nil
+	})
+}
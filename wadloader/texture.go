@@ -0,0 +1,246 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+)
+
+// composite wall textures: PNAMES lists the patch lump names and TEXTURE1/TEXTURE2
+// place those patches on each texture (Doom format, Strife's shorter entries aren't supported)
+type Texture struct {
+	Name    string
+	Masked  bool
+	Width   int16
+	Height  int16
+	Patches []TexturePatch
+}
+
+type TexturePatch struct {
+	OriginX   int16
+	OriginY   int16
+	PatchName string
+}
+
+type textureHeader struct {
+	Name            [8]byte
+	Masked          int32
+	Width           int16
+	Height          int16
+	ColumnDirectory int32
+	PatchCount      int16
+}
+
+type texturePatchEntry struct {
+	OriginX  int16
+	OriginY  int16
+	PatchIdx int16
+	StepDir  int16
+	Colormap int16
+}
+
+func (wl *WADLoader) DetectTextures() ([]Texture, error) {
+	var textures []Texture
+
+	pnamesLump, found := wl.findLastLump("PNAMES")
+	if !found {
+		return textures, errors.New("[Warn] DetectTextures: No PNAMES lump, cannot detect textures")
+	}
+
+	patchNames, err := wl.readPatchNames(pnamesLump)
+	if err != nil {
+		return textures, err
+	}
+
+	for _, textureLumpName := range []string{"TEXTURE1", "TEXTURE2"} {
+		textureLump, found := wl.findLastLump(textureLumpName)
+		if !found {
+			continue
+		}
+
+		lumpTextures, err := wl.readTextureLump(textureLump, patchNames)
+		if err != nil {
+			return textures, err
+		}
+
+		textures = append(textures, lumpTextures...)
+	}
+
+	if len(textures) < 1 {
+		return textures, errors.New("[Warn] DetectTextures: Couldn't detect textures")
+	}
+
+	return textures, nil
+}
+
+func (wl *WADLoader) LoadTextures() error {
+	textures, err := wl.DetectTextures()
+	if err != nil {
+		return err
+	}
+
+	wl.Textures = textures
+
+	return nil
+}
+
+// later lumps override the earlier ones with the same name
+func (wl *WADLoader) findLastLump(name string) (Lump, bool) {
+	for idx := len(wl.WADLumps) - 1; idx >= 0; idx-- {
+		lump := wl.WADLumps[idx]
+		if string(bytes.Trim(lump.LumpName[:], "\x00")) == name && wl.IsLumpInBounds(lump) {
+			return lump, true
+		}
+	}
+
+	return Lump{}, false
+}
+
+func (wl *WADLoader) readPatchNames(pnamesLump Lump) ([]string, error) {
+	lumpData, err := wl.ReadLumpData(pnamesLump)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lumpData) < 4 {
+		return nil, errors.New("[Error] readPatchNames: PNAMES is too short")
+	}
+
+	nameCount := int(binary.LittleEndian.Uint32(lumpData))
+	if 4+nameCount*8 > len(lumpData) {
+		return nil, errors.New("[Error] readPatchNames: PNAMES lists more names than it holds")
+	}
+
+	patchNames := make([]string, nameCount)
+	for i := range patchNames {
+		patchNames[i] = string(bytes.ToUpper(bytes.Trim(lumpData[4+i*8:4+(i+1)*8], "\x00")))
+	}
+
+	return patchNames, nil
+}
+
+func (wl *WADLoader) readTextureLump(textureLump Lump, patchNames []string) ([]Texture, error) {
+	lumpData, err := wl.ReadLumpData(textureLump)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lumpData) < 4 {
+		return nil, errors.New("[Error] readTextureLump: The texture lump is too short")
+	}
+
+	textureCount := int(binary.LittleEndian.Uint32(lumpData))
+	if textureCount < 0 || textureCount > (len(lumpData)-4)/4 {
+		return nil, errors.New("[Error] readTextureLump: The texture lump lists more textures than it holds")
+	}
+
+	textures := make([]Texture, 0, textureCount)
+
+	for i := 0; i < textureCount; i++ {
+		textureOffset := int(binary.LittleEndian.Uint32(lumpData[4+i*4:]))
+
+		var header textureHeader
+		// the offset goes negative on 32 bits builds when its top bit is set
+		if textureOffset < 0 || textureOffset > len(lumpData)-22 || binary.Read(bytes.NewReader(lumpData[textureOffset:]), binary.LittleEndian, &header) != nil {
+			fmt.Println("[Warn] readTextureLump: Texture", i, "points outside of the lump, skipping")
+			continue
+		}
+
+		texture := Texture{
+			Name:   string(bytes.ToUpper(bytes.Trim(header.Name[:], "\x00"))),
+			Masked: header.Masked != 0,
+			Width:  header.Width,
+			Height: header.Height,
+		}
+
+		patchesData := lumpData[textureOffset+22:]
+		if header.PatchCount < 0 || int(header.PatchCount) > len(patchesData)/10 {
+			fmt.Println("[Warn] readTextureLump: " + texture.Name + " lists more patches than the lump holds, skipping")
+			continue
+		}
+
+		patchEntries := make([]texturePatchEntry, header.PatchCount)
+
+		err := binary.Read(bytes.NewReader(patchesData), binary.LittleEndian, &patchEntries)
+		if err != nil {
+			fmt.Println("[Warn] readTextureLump: Cannot read the patches of " + texture.Name + ", skipping")
+			continue
+		}
+
+		for _, patchEntry := range patchEntries {
+			if patchEntry.PatchIdx < 0 || int(patchEntry.PatchIdx) >= len(patchNames) {
+				fmt.Println("[Warn] readTextureLump: " + texture.Name + " uses a patch missing from PNAMES")
+				continue
+			}
+
+			texture.Patches = append(texture.Patches, TexturePatch{
+				OriginX:   patchEntry.OriginX,
+				OriginY:   patchEntry.OriginY,
+				PatchName: patchNames[patchEntry.PatchIdx],
+			})
+		}
+
+		textures = append(textures, texture)
+	}
+
+	return textures, nil
+}
+
+// patches already loaded are reused, the rest are read from the lump with the same name
+func (wl *WADLoader) lookupPatch(name string, cache map[string]*Patch) (*Patch, error) {
+	if patch, found := cache[name]; found {
+		return patch, nil
+	}
+
+	for idx := range wl.Patches {
+		if wl.Patches[idx].Name == name {
+			cache[name] = &wl.Patches[idx]
+			return cache[name], nil
+		}
+	}
+
+	patchLump, found := wl.findLastLump(name)
+	if !found {
+		return nil, errors.New("[Error] lookupPatch: No lump for patch " + name)
+	}
+
+	patch, err := wl.parsePatchLump(patchLump)
+	if err != nil {
+		return nil, err
+	}
+
+	cache[name] = &patch
+
+	return &patch, nil
+}
+
+// patches are drawn in order, pixels not covered by any patch are left transparent
+func (wl *WADLoader) ComposeTexture(texture Texture, palette Palette, patchCache map[string]*Patch) (*image.RGBA, error) {
+	textureImg := image.NewRGBA(image.Rect(0, 0, int(texture.Width), int(texture.Height)))
+
+	for _, texturePatch := range texture.Patches {
+		patch, err := wl.lookupPatch(texturePatch.PatchName, patchCache)
+		if err != nil {
+			return textureImg, errors.New("[Error] ComposeTexture: Cannot compose " + texture.Name + " - " + err.Error())
+		}
+
+		for column, post := range patch.PatchPosts {
+			x := int(texturePatch.OriginX) + column
+
+			for _, postSegment := range post {
+				for i := 0; i < int(postSegment.Length); i++ {
+					y := int(texturePatch.OriginY) + int(postSegment.TopOffset) + i
+					pixelColor := palette[postSegment.PixelData[i]]
+
+					// Set ignores the pixels outside of the texture
+					textureImg.Set(x, y, color.RGBA{pixelColor.Red, pixelColor.Green, pixelColor.Blue, 255})
+				}
+			}
+		}
+	}
+
+	return textureImg, nil
+}
@@ -0,0 +1,601 @@
+package wadloader
+
+// editor numbers of the things and linedef specials used by each game profile,
+// decorations aren't listed and end up counted as unknown things
+
+var doomThingTypes = map[uint16]ThingInfo{
+	1:  {"Player 1 start", ThingCategoryPlayer, 0},
+	2:  {"Player 2 start", ThingCategoryPlayer, 0},
+	3:  {"Player 3 start", ThingCategoryPlayer, 0},
+	4:  {"Player 4 start", ThingCategoryPlayer, 0},
+	11: {"Deathmatch start", ThingCategoryPlayer, 0},
+	14: {"Teleport landing", ThingCategoryOther, 0},
+
+	3004: {"Zombieman", ThingCategoryMonster, 20},
+	9:    {"Shotgun guy", ThingCategoryMonster, 30},
+	65:   {"Heavy weapon dude", ThingCategoryMonster, 70},
+	84:   {"Wolfenstein SS", ThingCategoryMonster, 50},
+	3001: {"Imp", ThingCategoryMonster, 60},
+	3002: {"Demon", ThingCategoryMonster, 150},
+	58:   {"Spectre", ThingCategoryMonster, 150},
+	3006: {"Lost soul", ThingCategoryMonster, 100},
+	3005: {"Cacodemon", ThingCategoryMonster, 400},
+	69:   {"Hell knight", ThingCategoryMonster, 500},
+	3003: {"Baron of Hell", ThingCategoryMonster, 1000},
+	68:   {"Arachnotron", ThingCategoryMonster, 500},
+	71:   {"Pain elemental", ThingCategoryMonster, 400},
+	66:   {"Revenant", ThingCategoryMonster, 300},
+	67:   {"Mancubus", ThingCategoryMonster, 600},
+	64:   {"Arch-vile", ThingCategoryMonster, 700},
+	7:    {"Spider Mastermind", ThingCategoryMonster, 3000},
+	16:   {"Cyberdemon", ThingCategoryMonster, 4000},
+	72:   {"Commander Keen", ThingCategoryMonster, 100},
+	88:   {"Boss brain", ThingCategoryMonster, 250},
+	89:   {"Boss shooter", ThingCategoryOther, 0},
+	87:   {"Spawn spot", ThingCategoryOther, 0},
+
+	2005: {"Chainsaw", ThingCategoryWeapon, 0},
+	2001: {"Shotgun", ThingCategoryWeapon, 0},
+	82:   {"Super shotgun", ThingCategoryWeapon, 0},
+	2002: {"Chaingun", ThingCategoryWeapon, 0},
+	2003: {"Rocket launcher", ThingCategoryWeapon, 0},
+	2004: {"Plasma gun", ThingCategoryWeapon, 0},
+	2006: {"BFG9000", ThingCategoryWeapon, 0},
+
+	2007: {"Clip", ThingCategoryAmmo, 0},
+	2048: {"Box of bullets", ThingCategoryAmmo, 0},
+	2008: {"Shotgun shells", ThingCategoryAmmo, 0},
+	2049: {"Box of shells", ThingCategoryAmmo, 0},
+	2010: {"Rocket", ThingCategoryAmmo, 0},
+	2046: {"Box of rockets", ThingCategoryAmmo, 0},
+	2047: {"Energy cell", ThingCategoryAmmo, 0},
+	17:   {"Energy cell pack", ThingCategoryAmmo, 0},
+	8:    {"Backpack", ThingCategoryAmmo, 0},
+
+	2014: {"Health bonus", ThingCategoryHealth, 0},
+	2011: {"Stimpack", ThingCategoryHealth, 0},
+	2012: {"Medikit", ThingCategoryHealth, 0},
+	2013: {"Soulsphere", ThingCategoryHealth, 0},
+	83:   {"Megasphere", ThingCategoryHealth, 0},
+
+	2015: {"Armor bonus", ThingCategoryArmor, 0},
+	2018: {"Armor", ThingCategoryArmor, 0},
+	2019: {"Megaarmor", ThingCategoryArmor, 0},
+
+	2022: {"Invulnerability", ThingCategoryPowerup, 0},
+	2023: {"Berserk", ThingCategoryPowerup, 0},
+	2024: {"Partial invisibility", ThingCategoryPowerup, 0},
+	2025: {"Radiation shielding suit", ThingCategoryPowerup, 0},
+	2026: {"Computer area map", ThingCategoryPowerup, 0},
+	2045: {"Light amplification visor", ThingCategoryPowerup, 0},
+
+	5:  {"Blue keycard", ThingCategoryKey, 0},
+	6:  {"Yellow keycard", ThingCategoryKey, 0},
+	13: {"Red keycard", ThingCategoryKey, 0},
+	40: {"Blue skull key", ThingCategoryKey, 0},
+	39: {"Yellow skull key", ThingCategoryKey, 0},
+	38: {"Red skull key", ThingCategoryKey, 0},
+
+	2035: {"Exploding barrel", ThingCategoryOther, 0},
+}
+
+var chexThingTypes = overrideThingTypes(doomThingTypes, map[uint16]ThingInfo{
+	3004: {"Flemoidus commonus", ThingCategoryMonster, 20},
+	9:    {"Flemoidus bipedicus", ThingCategoryMonster, 30},
+	3001: {"Armored flemoidus bipedicus", ThingCategoryMonster, 60},
+	3002: {"Flemoidus cycloptis commonus", ThingCategoryMonster, 150},
+	58:   {"Chex larva", ThingCategoryMonster, 150},
+	3003: {"The Flembrane", ThingCategoryMonster, 1000},
+
+	2005: {"Super bootspork", ThingCategoryWeapon, 0},
+	2001: {"Large zorcher", ThingCategoryWeapon, 0},
+	2002: {"Rapid zorcher", ThingCategoryWeapon, 0},
+	2003: {"Zorch propulsor", ThingCategoryWeapon, 0},
+	2004: {"Phasing zorcher", ThingCategoryWeapon, 0},
+	2006: {"LAZ device", ThingCategoryWeapon, 0},
+
+	2007: {"Mini zorch recharge", ThingCategoryAmmo, 0},
+	2048: {"Mini zorch pack", ThingCategoryAmmo, 0},
+	2008: {"Large zorcher recharge", ThingCategoryAmmo, 0},
+	2049: {"Large zorcher pack", ThingCategoryAmmo, 0},
+	2010: {"Propulsor zorch", ThingCategoryAmmo, 0},
+	2046: {"Propulsor zorch pack", ThingCategoryAmmo, 0},
+	2047: {"Phasing zorcher recharge", ThingCategoryAmmo, 0},
+	17:   {"Phasing zorcher pack", ThingCategoryAmmo, 0},
+	8:    {"Zorchpack", ThingCategoryAmmo, 0},
+
+	2014: {"Glass of water", ThingCategoryHealth, 0},
+	2011: {"Bowl of fruit", ThingCategoryHealth, 0},
+	2012: {"Bowl of vegetables", ThingCategoryHealth, 0},
+	2013: {"Supercharge breakfast", ThingCategoryHealth, 0},
+
+	2015: {"Slime repellent", ThingCategoryArmor, 0},
+	2018: {"Chex armor", ThingCategoryArmor, 0},
+	2019: {"Super Chex armor", ThingCategoryArmor, 0},
+
+	2025: {"Slimeproof suit", ThingCategoryPowerup, 0},
+
+	5:  {"Blue key", ThingCategoryKey, 0},
+	6:  {"Yellow key", ThingCategoryKey, 0},
+	13: {"Red key", ThingCategoryKey, 0},
+})
+
+var hereticThingTypes = map[uint16]ThingInfo{
+	1:  {"Player 1 start", ThingCategoryPlayer, 0},
+	2:  {"Player 2 start", ThingCategoryPlayer, 0},
+	3:  {"Player 3 start", ThingCategoryPlayer, 0},
+	4:  {"Player 4 start", ThingCategoryPlayer, 0},
+	11: {"Deathmatch start", ThingCategoryPlayer, 0},
+	14: {"Teleport landing", ThingCategoryOther, 0},
+
+	66: {"Gargoyle", ThingCategoryMonster, 40},
+	5:  {"Fire gargoyle", ThingCategoryMonster, 80},
+	68: {"Golem", ThingCategoryMonster, 80},
+	69: {"Golem ghost", ThingCategoryMonster, 80},
+	45: {"Nitrogolem", ThingCategoryMonster, 100},
+	46: {"Nitrogolem ghost", ThingCategoryMonster, 100},
+	90: {"Sabreclaw", ThingCategoryMonster, 150},
+	64: {"Undead warrior", ThingCategoryMonster, 200},
+	65: {"Undead warrior ghost", ThingCategoryMonster, 200},
+	15: {"Disciple of D'Sparil", ThingCategoryMonster, 180},
+	70: {"Weredragon", ThingCategoryMonster, 220},
+	92: {"Ophidian", ThingCategoryMonster, 280},
+	6:  {"Iron lich", ThingCategoryMonster, 700},
+	9:  {"Maulotaur", ThingCategoryMonster, 3000},
+	7:  {"D'Sparil", ThingCategoryMonster, 2000},
+
+	2005: {"Gauntlets of the Necromancer", ThingCategoryWeapon, 0},
+	2001: {"Ethereal crossbow", ThingCategoryWeapon, 0},
+	53:   {"Dragon claw", ThingCategoryWeapon, 0},
+	2004: {"Hellstaff", ThingCategoryWeapon, 0},
+	2003: {"Phoenix rod", ThingCategoryWeapon, 0},
+	2002: {"Firemace", ThingCategoryWeapon, 0},
+
+	10: {"Wand crystal", ThingCategoryAmmo, 0},
+	12: {"Crystal geode", ThingCategoryAmmo, 0},
+	18: {"Ethereal arrows", ThingCategoryAmmo, 0},
+	19: {"Quiver of ethereal arrows", ThingCategoryAmmo, 0},
+	54: {"Claw orb", ThingCategoryAmmo, 0},
+	55: {"Energy orb", ThingCategoryAmmo, 0},
+	20: {"Lesser runes", ThingCategoryAmmo, 0},
+	21: {"Greater runes", ThingCategoryAmmo, 0},
+	22: {"Flame orb", ThingCategoryAmmo, 0},
+	23: {"Inferno orb", ThingCategoryAmmo, 0},
+	13: {"Mace spheres", ThingCategoryAmmo, 0},
+	16: {"Pile of mace spheres", ThingCategoryAmmo, 0},
+	8:  {"Bag of holding", ThingCategoryAmmo, 0},
+
+	81: {"Crystal vial", ThingCategoryHealth, 0},
+
+	85: {"Silver shield", ThingCategoryArmor, 0},
+	31: {"Enchanted shield", ThingCategoryArmor, 0},
+
+	73: {"Green key", ThingCategoryKey, 0},
+	79: {"Blue key", ThingCategoryKey, 0},
+	80: {"Yellow key", ThingCategoryKey, 0},
+
+	82: {"Quartz flask", ThingCategoryArtifact, 0},
+	32: {"Mystic urn", ThingCategoryArtifact, 0},
+	33: {"Torch", ThingCategoryArtifact, 0},
+	34: {"Time bomb of the ancients", ThingCategoryArtifact, 0},
+	35: {"Map scroll", ThingCategoryPowerup, 0},
+	36: {"Chaos device", ThingCategoryArtifact, 0},
+	30: {"Morph ovum", ThingCategoryArtifact, 0},
+	75: {"Shadowsphere", ThingCategoryArtifact, 0},
+	83: {"Wings of wrath", ThingCategoryArtifact, 0},
+	84: {"Ring of invincibility", ThingCategoryArtifact, 0},
+	86: {"Tome of power", ThingCategoryArtifact, 0},
+}
+
+var hexenThingTypes = map[uint16]ThingInfo{
+	1:  {"Player 1 start", ThingCategoryPlayer, 0},
+	2:  {"Player 2 start", ThingCategoryPlayer, 0},
+	3:  {"Player 3 start", ThingCategoryPlayer, 0},
+	4:  {"Player 4 start", ThingCategoryPlayer, 0},
+	11: {"Deathmatch start", ThingCategoryPlayer, 0},
+	14: {"Teleport landing", ThingCategoryOther, 0},
+
+	10030: {"Ettin", ThingCategoryMonster, 175},
+	10060: {"Afrit", ThingCategoryMonster, 80},
+	107:   {"Centaur", ThingCategoryMonster, 200},
+	115:   {"Slaughtaur", ThingCategoryMonster, 250},
+	31:    {"Green chaos serpent", ThingCategoryMonster, 250},
+	8080:  {"Brown chaos serpent", ThingCategoryMonster, 250},
+	34:    {"Reiver", ThingCategoryMonster, 150},
+	10011: {"Buried reiver", ThingCategoryMonster, 150},
+	8020:  {"Wendigo", ThingCategoryMonster, 120},
+	114:   {"Dark bishop", ThingCategoryMonster, 130},
+	121:   {"Stalker", ThingCategoryMonster, 90},
+	120:   {"Stalker leader", ThingCategoryMonster, 90},
+	254:   {"Death wyvern", ThingCategoryMonster, 640},
+	10080: {"Heresiarch", ThingCategoryMonster, 5000},
+	10100: {"Zedek", ThingCategoryMonster, 800},
+	10101: {"Traductus", ThingCategoryMonster, 800},
+	10102: {"Menelkir", ThingCategoryMonster, 800},
+	10200: {"Korax", ThingCategoryMonster, 5000},
+
+	8010: {"Timon's axe", ThingCategoryWeapon, 0},
+	123:  {"Hammer of retribution", ThingCategoryWeapon, 0},
+	10:   {"Serpent staff", ThingCategoryWeapon, 0},
+	8009: {"Firestorm", ThingCategoryWeapon, 0},
+	53:   {"Frost shards", ThingCategoryWeapon, 0},
+	8040: {"Arc of death", ThingCategoryWeapon, 0},
+	12:   {"Quietus piece", ThingCategoryWeapon, 0},
+	13:   {"Quietus piece", ThingCategoryWeapon, 0},
+	16:   {"Quietus piece", ThingCategoryWeapon, 0},
+	18:   {"Wraithverge piece", ThingCategoryWeapon, 0},
+	19:   {"Wraithverge piece", ThingCategoryWeapon, 0},
+	20:   {"Wraithverge piece", ThingCategoryWeapon, 0},
+	21:   {"Bloodscourge piece", ThingCategoryWeapon, 0},
+	22:   {"Bloodscourge piece", ThingCategoryWeapon, 0},
+	23:   {"Bloodscourge piece", ThingCategoryWeapon, 0},
+
+	122:  {"Blue mana", ThingCategoryAmmo, 0},
+	124:  {"Green mana", ThingCategoryAmmo, 0},
+	8004: {"Combined mana", ThingCategoryAmmo, 0},
+
+	81: {"Crystal vial", ThingCategoryHealth, 0},
+
+	8005: {"Mesh armor", ThingCategoryArmor, 0},
+	8006: {"Falcon shield", ThingCategoryArmor, 0},
+	8007: {"Platinum helmet", ThingCategoryArmor, 0},
+	8008: {"Amulet of warding", ThingCategoryArmor, 0},
+
+	8030: {"Steel key", ThingCategoryKey, 0},
+	8031: {"Cave key", ThingCategoryKey, 0},
+	8032: {"Axe key", ThingCategoryKey, 0},
+	8033: {"Fire key", ThingCategoryKey, 0},
+	8034: {"Emerald key", ThingCategoryKey, 0},
+	8035: {"Dungeon key", ThingCategoryKey, 0},
+	8036: {"Silver key", ThingCategoryKey, 0},
+	8037: {"Rusted key", ThingCategoryKey, 0},
+	8038: {"Horn key", ThingCategoryKey, 0},
+	8039: {"Swamp key", ThingCategoryKey, 0},
+	8200: {"Castle key", ThingCategoryKey, 0},
+
+	82:    {"Quartz flask", ThingCategoryArtifact, 0},
+	32:    {"Mystic urn", ThingCategoryArtifact, 0},
+	84:    {"Icon of the defender", ThingCategoryArtifact, 0},
+	30:    {"Porkalator", ThingCategoryArtifact, 0},
+	36:    {"Chaos device", ThingCategoryArtifact, 0},
+	10040: {"Banishment device", ThingCategoryArtifact, 0},
+	8000:  {"Flechette", ThingCategoryArtifact, 0},
+	86:    {"Dark servant", ThingCategoryArtifact, 0},
+	10110: {"Disc of repulsion", ThingCategoryArtifact, 0},
+	83:    {"Wings of wrath", ThingCategoryArtifact, 0},
+	33:    {"Torch", ThingCategoryArtifact, 0},
+	8041:  {"Dragonskin bracers", ThingCategoryArtifact, 0},
+	8003:  {"Krater of might", ThingCategoryArtifact, 0},
+	10120: {"Mystic ambit incant", ThingCategoryArtifact, 0},
+	8002:  {"Boots of speed", ThingCategoryArtifact, 0},
+}
+
+var strifeThingTypes = map[uint16]ThingInfo{
+	1:  {"Player 1 start", ThingCategoryPlayer, 0},
+	2:  {"Player 2 start", ThingCategoryPlayer, 0},
+	3:  {"Player 3 start", ThingCategoryPlayer, 0},
+	4:  {"Player 4 start", ThingCategoryPlayer, 0},
+	5:  {"Player 5 start", ThingCategoryPlayer, 0},
+	6:  {"Player 6 start", ThingCategoryPlayer, 0},
+	7:  {"Player 7 start", ThingCategoryPlayer, 0},
+	8:  {"Player 8 start", ThingCategoryPlayer, 0},
+	11: {"Deathmatch start", ThingCategoryPlayer, 0},
+
+	3002: {"Acolyte", ThingCategoryMonster, 70},
+	3001: {"Reaver", ThingCategoryMonster, 150},
+	186:  {"Stalker", ThingCategoryMonster, 80},
+	3006: {"Sentinel", ThingCategoryMonster, 100},
+	3005: {"Crusader", ThingCategoryMonster, 400},
+	3003: {"Templar", ThingCategoryMonster, 300},
+	16:   {"Inquisitor", ThingCategoryMonster, 1000},
+	71:   {"Programmer", ThingCategoryMonster, 1100},
+	64:   {"Bishop", ThingCategoryMonster, 500},
+	12:   {"Loremaster", ThingCategoryMonster, 700},
+
+	2001: {"Crossbow", ThingCategoryWeapon, 0},
+	2002: {"Assault gun", ThingCategoryWeapon, 0},
+	2003: {"Mini-missile launcher", ThingCategoryWeapon, 0},
+	2004: {"Mauler", ThingCategoryWeapon, 0},
+	2005: {"Flamethrower", ThingCategoryWeapon, 0},
+	154:  {"Grenade launcher", ThingCategoryWeapon, 0},
+
+	2007: {"Clip of bullets", ThingCategoryAmmo, 0},
+	2048: {"Box of bullets", ThingCategoryAmmo, 0},
+	2010: {"Mini missiles", ThingCategoryAmmo, 0},
+	2046: {"Crate of missiles", ThingCategoryAmmo, 0},
+	2047: {"Energy pod", ThingCategoryAmmo, 0},
+	17:   {"Energy pack", ThingCategoryAmmo, 0},
+	114:  {"Electric bolts", ThingCategoryAmmo, 0},
+	115:  {"Poison bolts", ThingCategoryAmmo, 0},
+	152:  {"HE-grenade rounds", ThingCategoryAmmo, 0},
+	153:  {"Phosphorus-grenade rounds", ThingCategoryAmmo, 0},
+
+	2011: {"Med patch", ThingCategoryHealth, 0},
+	2012: {"Medical kit", ThingCategoryHealth, 0},
+	83:   {"Surgery kit", ThingCategoryHealth, 0},
+
+	2018: {"Leather armor", ThingCategoryArmor, 0},
+	2019: {"Metal armor", ThingCategoryArmor, 0},
+}
+
+// every special of vanilla Doom and Doom II, 78 and 85 are unused
+var doomLinedefTypes = map[uint16]string{
+	1:   "DR Door open wait close",
+	2:   "W1 Door open stay",
+	3:   "W1 Door close",
+	4:   "W1 Door open wait close",
+	5:   "W1 Floor raise to lowest ceiling",
+	6:   "W1 Crusher start (fast)",
+	7:   "S1 Stairs raise by 8",
+	8:   "W1 Stairs raise by 8",
+	9:   "S1 Donut",
+	10:  "W1 Lift lower wait raise",
+	11:  "S1 Exit level",
+	12:  "W1 Light change to brightest adjacent",
+	13:  "W1 Light change to 255",
+	14:  "S1 Floor raise by 32, change texture",
+	15:  "S1 Floor raise by 24, change texture",
+	16:  "W1 Door close wait open",
+	17:  "W1 Light start blinking",
+	18:  "S1 Floor raise to next higher",
+	19:  "W1 Floor lower to highest adjacent",
+	20:  "S1 Floor raise to next higher, change texture",
+	21:  "S1 Lift lower wait raise",
+	22:  "W1 Floor raise to next higher, change texture",
+	23:  "S1 Floor lower to lowest",
+	24:  "G1 Floor raise to lowest ceiling",
+	25:  "W1 Crusher start (slow)",
+	26:  "DR Blue door open wait close",
+	27:  "DR Yellow door open wait close",
+	28:  "DR Red door open wait close",
+	29:  "S1 Door open wait close",
+	30:  "W1 Floor raise by shortest lower texture",
+	31:  "D1 Door open stay",
+	32:  "D1 Blue door open stay",
+	33:  "D1 Red door open stay",
+	34:  "D1 Yellow door open stay",
+	35:  "W1 Light change to 35",
+	36:  "W1 Floor lower to 8 above highest",
+	37:  "W1 Floor lower to lowest, change texture and type",
+	38:  "W1 Floor lower to lowest",
+	39:  "W1 Teleport",
+	40:  "W1 Ceiling raise to highest",
+	41:  "S1 Ceiling lower to floor",
+	42:  "SR Door close",
+	43:  "SR Ceiling lower to floor",
+	44:  "W1 Ceiling lower to 8 above floor",
+	45:  "SR Floor lower to highest adjacent",
+	46:  "GR Door open stay",
+	47:  "G1 Floor raise to next higher, change texture",
+	48:  "Scroll texture left",
+	49:  "S1 Ceiling crush and raise (slow)",
+	50:  "S1 Door close",
+	51:  "S1 Exit to secret level",
+	52:  "W1 Exit level",
+	53:  "W1 Lift start perpetual",
+	54:  "W1 Lift stop",
+	55:  "S1 Floor raise to 8 below lowest ceiling (crush)",
+	56:  "W1 Floor raise to 8 below lowest ceiling (crush)",
+	57:  "W1 Crusher stop",
+	58:  "W1 Floor raise by 24",
+	59:  "W1 Floor raise by 24, change texture and type",
+	60:  "SR Floor lower to lowest",
+	61:  "SR Door open stay",
+	62:  "SR Lift lower wait raise",
+	63:  "SR Door open wait close",
+	64:  "SR Floor raise to lowest ceiling",
+	65:  "SR Floor raise to 8 below lowest ceiling (crush)",
+	66:  "SR Floor raise by 24, change texture",
+	67:  "SR Floor raise by 32, change texture",
+	68:  "SR Floor raise to next higher, change texture",
+	69:  "SR Floor raise to next higher",
+	70:  "SR Floor lower to 8 above highest",
+	71:  "S1 Floor lower to 8 above highest",
+	72:  "WR Ceiling lower to 8 above floor",
+	73:  "WR Crusher start (slow)",
+	74:  "WR Crusher stop",
+	75:  "WR Door close",
+	76:  "WR Door close wait open",
+	77:  "WR Crusher start (fast)",
+	79:  "WR Light change to 35",
+	80:  "WR Light change to brightest adjacent",
+	81:  "WR Light change to 255",
+	82:  "WR Floor lower to lowest",
+	83:  "WR Floor lower to highest adjacent",
+	84:  "WR Floor lower to lowest, change texture and type",
+	86:  "WR Door open stay",
+	87:  "WR Lift start perpetual",
+	88:  "WR Lift lower wait raise",
+	89:  "WR Lift stop",
+	90:  "WR Door open wait close",
+	91:  "WR Floor raise to lowest ceiling",
+	92:  "WR Floor raise by 24",
+	93:  "WR Floor raise by 24, change texture and type",
+	94:  "WR Floor raise to 8 below lowest ceiling (crush)",
+	95:  "WR Floor raise to next higher, change texture",
+	96:  "WR Floor raise by shortest lower texture",
+	97:  "WR Teleport",
+	98:  "WR Floor lower to 8 above highest",
+	99:  "SR Blue door open stay (fast)",
+	100: "W1 Stairs raise by 16 (fast)",
+	101: "S1 Floor raise to lowest ceiling",
+	102: "S1 Floor lower to highest adjacent",
+	103: "S1 Door open stay",
+	104: "W1 Light change to darkest adjacent",
+	105: "WR Door open wait close (fast)",
+	106: "WR Door open stay (fast)",
+	107: "WR Door close (fast)",
+	108: "W1 Door open wait close (fast)",
+	109: "W1 Door open stay (fast)",
+	110: "W1 Door close (fast)",
+	111: "S1 Door open wait close (fast)",
+	112: "S1 Door open stay (fast)",
+	113: "S1 Door close (fast)",
+	114: "SR Door open wait close (fast)",
+	115: "SR Door open stay (fast)",
+	116: "SR Door close (fast)",
+	117: "DR Door open wait close (fast)",
+	118: "D1 Door open stay (fast)",
+	119: "W1 Floor raise to next higher",
+	120: "WR Lift lower wait raise (fast)",
+	121: "W1 Lift lower wait raise (fast)",
+	122: "S1 Lift lower wait raise (fast)",
+	123: "SR Lift lower wait raise (fast)",
+	124: "W1 Exit to secret level",
+	125: "W1 Teleport (monsters only)",
+	126: "WR Teleport (monsters only)",
+	127: "S1 Stairs raise by 16 (fast)",
+	128: "WR Floor raise to next higher",
+	129: "WR Floor raise to next higher (fast)",
+	130: "W1 Floor raise to next higher (fast)",
+	131: "S1 Floor raise to next higher (fast)",
+	132: "SR Floor raise to next higher (fast)",
+	133: "S1 Blue door open stay (fast)",
+	134: "SR Red door open stay (fast)",
+	135: "S1 Red door open stay (fast)",
+	136: "SR Yellow door open stay (fast)",
+	137: "S1 Yellow door open stay (fast)",
+	138: "SR Light change to 255",
+	139: "SR Light change to 35",
+	140: "S1 Floor raise by 512",
+	141: "W1 Crusher start (silent)",
+}
+
+// Heretic keeps the vanilla Doom specials below 100 and uses 99 to scroll right
+var hereticLinedefTypes = overrideLinedefTypes(doomLinedefTypes, 99, map[uint16]string{
+	99: "Scroll texture right",
+})
+
+// Strife keeps most of the vanilla Doom specials, exits go to the map in tag / 100 and the
+// new ones drive the quests, voices, alarms and rifts
+var strifeLinedefTypes = overrideLinedefTypes(doomLinedefTypes, 142, map[uint16]string{
+	40:  "W1 Ceiling raise to highest, floor lower to lowest",
+	52:  "W1 Exit to map in tag",
+	145: "W1 Exit to map in tag (rift)",
+	182: "G1 Break glass",
+	186: "WR Exit to map in tag (rift)",
+	194: "S1 Free prisoners",
+	198: "WR Raise alarm without a guard uniform",
+	201: "W1 Play voice in tag",
+	204: "W1 Change music",
+	226: "S1 Complete training",
+	230: "W1 Door open stay if quest in tag is done",
+})
+
+var hexenLinedefTypes = map[uint16]string{
+	1:   "Polyobj_StartLine",
+	2:   "Polyobj_RotateLeft",
+	3:   "Polyobj_RotateRight",
+	4:   "Polyobj_Move",
+	5:   "Polyobj_ExplicitLine",
+	6:   "Polyobj_MoveTimes8",
+	7:   "Polyobj_DoorSwing",
+	8:   "Polyobj_DoorSlide",
+	10:  "Door_Close",
+	11:  "Door_Open",
+	12:  "Door_Raise",
+	13:  "Door_LockedRaise",
+	20:  "Floor_LowerByValue",
+	21:  "Floor_LowerToLowest",
+	22:  "Floor_LowerToNearest",
+	23:  "Floor_RaiseByValue",
+	24:  "Floor_RaiseToHighest",
+	25:  "Floor_RaiseToNearest",
+	26:  "Stairs_BuildDown",
+	27:  "Stairs_BuildUp",
+	28:  "Floor_RaiseAndCrush",
+	29:  "Pillar_Build",
+	30:  "Pillar_Open",
+	31:  "Stairs_BuildDownSync",
+	32:  "Stairs_BuildUpSync",
+	35:  "Floor_RaiseByValueTimes8",
+	36:  "Floor_LowerByValueTimes8",
+	40:  "Ceiling_LowerByValue",
+	41:  "Ceiling_RaiseByValue",
+	42:  "Ceiling_CrushAndRaise",
+	43:  "Ceiling_LowerAndCrush",
+	44:  "Ceiling_CrushStop",
+	45:  "Ceiling_CrushRaiseAndStay",
+	46:  "Floor_CrushStop",
+	60:  "Plat_PerpetualRaise",
+	61:  "Plat_Stop",
+	62:  "Plat_DownWaitUpStay",
+	63:  "Plat_DownByValue",
+	64:  "Plat_UpWaitDownStay",
+	65:  "Plat_UpByValue",
+	66:  "Floor_LowerInstant",
+	67:  "Floor_RaiseInstant",
+	68:  "Floor_MoveToValueTimes8",
+	69:  "Ceiling_MoveToValueTimes8",
+	70:  "Teleport",
+	71:  "Teleport_NoFog",
+	72:  "ThrustThing",
+	73:  "DamageThing",
+	74:  "Teleport_NewMap",
+	75:  "Teleport_EndGame",
+	80:  "ACS_Execute",
+	81:  "ACS_Suspend",
+	82:  "ACS_Terminate",
+	83:  "ACS_LockedExecute",
+	90:  "Polyobj_OR_RotateLeft",
+	91:  "Polyobj_OR_RotateRight",
+	92:  "Polyobj_OR_Move",
+	93:  "Polyobj_OR_MoveTimes8",
+	94:  "Pillar_BuildAndCrush",
+	95:  "FloorAndCeiling_LowerByValue",
+	96:  "FloorAndCeiling_RaiseByValue",
+	100: "Scroll_Texture_Left",
+	101: "Scroll_Texture_Right",
+	102: "Scroll_Texture_Up",
+	103: "Scroll_Texture_Down",
+	109: "Light_ForceLightning",
+	110: "Light_RaiseByValue",
+	111: "Light_LowerByValue",
+	112: "Light_ChangeToValue",
+	113: "Light_Fade",
+	114: "Light_Glow",
+	115: "Light_Flicker",
+	116: "Light_Strobe",
+	120: "Radius_Quake",
+	121: "Line_SetIdentification",
+	129: "UsePuzzleItem",
+	130: "Thing_Activate",
+	131: "Thing_Deactivate",
+	132: "Thing_Remove",
+	133: "Thing_Destroy",
+	134: "Thing_Projectile",
+	135: "Thing_Spawn",
+	136: "Thing_ProjectileGravity",
+	137: "Thing_SpawnNoFog",
+	138: "Floor_Waggle",
+	140: "Sector_ChangeSound",
+}
+
+func overrideThingTypes(base map[uint16]ThingInfo, overrides map[uint16]ThingInfo) map[uint16]ThingInfo {
+	thingTypes := make(map[uint16]ThingInfo, len(base))
+	for thingType, info := range base {
+		thingTypes[thingType] = info
+	}
+
+	for thingType, info := range overrides {
+		thingTypes[thingType] = info
+	}
+
+	return thingTypes
+}
+
+// only the base types below maxType are kept
+func overrideLinedefTypes(base map[uint16]string, maxType uint16, overrides map[uint16]string) map[uint16]string {
+	linedefTypes := make(map[uint16]string, len(base))
+	for lineType, name := range base {
+		if lineType < maxType {
+			linedefTypes[lineType] = name
+		}
+	}
+
+	for lineType, name := range overrides {
+		linedefTypes[lineType] = name
+	}
+
+	return linedefTypes
+}
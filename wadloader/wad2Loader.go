@@ -0,0 +1,382 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Quake (WAD2) and Half-Life (WAD3) texture archives
+const (
+	WAD2TypePalette   = 0x40
+	WAD2TypeQPic      = 0x42
+	WAD2TypeMipTexHL  = 0x43
+	WAD2TypeMipTex    = 0x44
+	WAD2TypeConsole   = 0x45
+	WAD2TypeFont      = 0x46
+	WAD2CompressNone  = 0
+	WAD2CompressLZSS  = 1
+	wad2EntryByteSize = 32
+)
+
+type WAD2Loader struct {
+	WADFilename string
+	WADReader   io.ReaderAt
+	WADSize     int64
+	WADHeader   WADHeader
+	Entries     []WAD2Entry
+
+	// used by WAD2 textures, WAD3 textures carry their own palette
+	Palette    Palette
+	HasPalette bool
+	Textures   []MipTexture
+
+	wadCloser io.Closer
+}
+
+type WAD2Entry struct {
+	EntryOffset uint32
+	DiskSize    uint32
+	Size        uint32
+	Type        uint8
+	Compression uint8
+	Padding     uint16
+	EntryName   [16]byte
+}
+
+type MipTexture struct {
+	Name       string
+	Width      uint32
+	Height     uint32
+	MipOffsets [4]uint32
+	MipLevels  [4][]byte
+	Palette    *Palette
+}
+
+func (e WAD2Entry) Name() string {
+	nameEnd := bytes.IndexByte(e.EntryName[:], 0)
+	if nameEnd == -1 {
+		nameEnd = len(e.EntryName)
+	}
+	return string(e.EntryName[:nameEnd])
+}
+
+func (wl *WAD2Loader) OpenAndLoad(wadFilename string) error {
+	wl.WADFilename = wadFilename
+
+	wadFile, err := os.Open(wadFilename)
+	if err != nil {
+		return errors.New("[Error] OpenAndLoad: Couldn't open the WAD file - " + err.Error())
+	}
+
+	fileInfo, err := wadFile.Stat()
+	if err != nil {
+		wadFile.Close()
+		return errors.New("[Error] OpenAndLoad: Couldn't get the WAD file size - " + err.Error())
+	}
+
+	err = wl.LoadFromReaderAt(wadFile, fileInfo.Size())
+	if err != nil {
+		wadFile.Close()
+		return err
+	}
+
+	wl.wadCloser = wadFile
+
+	return nil
+}
+
+func (wl *WAD2Loader) Close() error {
+	if wl.wadCloser == nil {
+		return nil
+	}
+
+	err := wl.wadCloser.Close()
+	wl.wadCloser = nil
+
+	return err
+}
+
+func (wl *WAD2Loader) LoadFromReaderAt(r io.ReaderAt, size int64) error {
+	if size < 12 {
+		return errors.New("[Error] LoadFromReaderAt: Not enough data to read the WAD header")
+	}
+
+	wl.WADReader = r
+	wl.WADSize = size
+
+	err := binary.Read(io.NewSectionReader(r, 0, 12), binary.LittleEndian, &wl.WADHeader)
+	if err != nil {
+		return errors.New("[Error] LoadFromReaderAt: Invalid data when reading the WAD Header - " + err.Error())
+	}
+
+	wadType := string(wl.WADHeader.WadType[:])
+	if wadType != "WAD2" && wadType != "WAD3" {
+		return errors.New("[Error] LoadFromReaderAt: Not a WAD2/WAD3 file")
+	}
+
+	return wl.readEntries()
+}
+
+func (wl *WAD2Loader) IsWAD3() bool {
+	return string(wl.WADHeader.WadType[:]) == "WAD3"
+}
+
+func (wl *WAD2Loader) readEntries() error {
+	dirOffset := int64(wl.WADHeader.LumpDirectoryOffset)
+	dirSize := int64(wl.WADHeader.LumpEntries) * wad2EntryByteSize
+
+	if dirOffset+dirSize > wl.WADSize {
+		return errors.New("[Error] readEntries: The WAD directory points outside of the file")
+	}
+
+	wl.Entries = make([]WAD2Entry, wl.WADHeader.LumpEntries)
+
+	err := binary.Read(io.NewSectionReader(wl.WADReader, dirOffset, dirSize), binary.LittleEndian, &wl.Entries)
+	if err != nil {
+		return errors.New("[Error] readEntries: Invalid data when reading the WAD directory - " + err.Error())
+	}
+
+	return nil
+}
+
+func (wl *WAD2Loader) ReadEntryData(entry WAD2Entry) ([]byte, error) {
+	if entry.Compression != WAD2CompressNone {
+		return nil, errors.New("[Error] ReadEntryData: " + entry.Name() + " is compressed, compressed entries aren't supported")
+	}
+
+	if int64(entry.EntryOffset)+int64(entry.DiskSize) > wl.WADSize {
+		return nil, errors.New("[Error] ReadEntryData: " + entry.Name() + " points outside of the WAD file")
+	}
+
+	data := make([]byte, entry.DiskSize)
+
+	_, err := io.ReadFull(io.NewSectionReader(wl.WADReader, int64(entry.EntryOffset), int64(entry.DiskSize)), data)
+	if err != nil {
+		return nil, errors.New("[Error] ReadEntryData: Cannot read " + entry.Name() + " - " + err.Error())
+	}
+
+	return data, nil
+}
+
+// WAD2 archives may carry the Quake palette in a PALETTE entry
+func (wl *WAD2Loader) DetectPalette() error {
+	for _, entry := range wl.Entries {
+		if entry.Type != WAD2TypePalette || !strings.EqualFold(entry.Name(), "PALETTE") {
+			continue
+		}
+
+		data, err := wl.ReadEntryData(entry)
+		if err != nil {
+			return err
+		}
+
+		palette, err := PaletteFromRawData(data)
+		if err != nil {
+			return errors.New("[Error] DetectPalette: Cannot read the palette - " + err.Error())
+		}
+
+		wl.Palette = palette
+		wl.HasPalette = true
+
+		return nil
+	}
+
+	return errors.New("[Warn] DetectPalette: No PALETTE entry in the WAD")
+}
+
+func (wl *WAD2Loader) LoadTextures() error {
+	wl.Textures = nil
+
+	for _, entry := range wl.Entries {
+		if entry.Type != WAD2TypeMipTex && entry.Type != WAD2TypeMipTexHL {
+			continue
+		}
+
+		data, err := wl.ReadEntryData(entry)
+		if err != nil {
+			fmt.Println("[Warn] LoadTextures: Skipping texture - " + err.Error())
+			continue
+		}
+
+		texture, err := parseMipTexture(data, entry.Type == WAD2TypeMipTexHL)
+		if err != nil {
+			fmt.Println("[Warn] LoadTextures: Skipping texture " + entry.Name() + " - " + err.Error())
+			continue
+		}
+
+		wl.Textures = append(wl.Textures, texture)
+	}
+
+	return nil
+}
+
+func parseMipTexture(data []byte, hasPalette bool) (MipTexture, error) {
+	var texture MipTexture
+
+	var mipHeader struct {
+		Name       [16]byte
+		Width      uint32
+		Height     uint32
+		MipOffsets [4]uint32
+	}
+
+	err := binary.Read(bytes.NewReader(data), binary.LittleEndian, &mipHeader)
+	if err != nil {
+		return texture, errors.New("[Error] parseMipTexture: Cannot read the miptex header - " + err.Error())
+	}
+
+	nameEnd := bytes.IndexByte(mipHeader.Name[:], 0)
+	if nameEnd == -1 {
+		nameEnd = len(mipHeader.Name)
+	}
+
+	texture.Name = string(mipHeader.Name[:nameEnd])
+	texture.Width = mipHeader.Width
+	texture.Height = mipHeader.Height
+	texture.MipOffsets = mipHeader.MipOffsets
+
+	if texture.Width == 0 || texture.Height == 0 || texture.Width > 4096 || texture.Height > 4096 {
+		return texture, errors.New("[Error] parseMipTexture: Invalid texture size")
+	}
+
+	// BSP textures can be declared without pixel data
+	if texture.MipOffsets[0] == 0 {
+		return texture, errors.New("[Error] parseMipTexture: The texture has no pixel data")
+	}
+
+	var mipEnd uint32
+
+	for level := 0; level < 4; level++ {
+		mipSize := (texture.Width >> level) * (texture.Height >> level)
+		mipStart := texture.MipOffsets[level]
+
+		if uint64(mipStart)+uint64(mipSize) > uint64(len(data)) {
+			return texture, errors.New("[Error] parseMipTexture: Mip level " + strconv.Itoa(level) + " exceeds the entry data")
+		}
+
+		texture.MipLevels[level] = data[mipStart : mipStart+mipSize]
+		mipEnd = mipStart + mipSize
+	}
+
+	if !hasPalette {
+		return texture, nil
+	}
+
+	// WAD3: color count followed by the palette right after the last mip level
+	if int(mipEnd)+2 > len(data) {
+		return texture, errors.New("[Error] parseMipTexture: Missing embedded palette")
+	}
+
+	colorCount := int(binary.LittleEndian.Uint16(data[mipEnd:]))
+	paletteStart := int(mipEnd) + 2
+
+	if colorCount > 256 || paletteStart+colorCount*3 > len(data) {
+		return texture, errors.New("[Error] parseMipTexture: Invalid embedded palette")
+	}
+
+	var palette Palette
+	for i := 0; i < colorCount; i++ {
+		palette[i] = PaletteColor{
+			Red:   data[paletteStart+i*3],
+			Green: data[paletteStart+i*3+1],
+			Blue:  data[paletteStart+i*3+2],
+		}
+	}
+
+	texture.Palette = &palette
+
+	return texture, nil
+}
+
+func PaletteFromRawData(data []byte) (Palette, error) {
+	var palette Palette
+
+	if len(data) < 768 {
+		return palette, errors.New("[Error] PaletteFromRawData: A palette needs 768 bytes")
+	}
+
+	err := binary.Read(bytes.NewReader(data[:768]), binary.LittleEndian, &palette)
+	if err != nil {
+		return palette, errors.New("[Error] PaletteFromRawData: Cannot read the palette - " + err.Error())
+	}
+
+	return palette, nil
+}
+
+func (wl *WAD2Loader) ExportAllTextures(outputFolder string, allMipLevels bool) error {
+	outputFolder, err := createFolder(outputFolder)
+	if err != nil {
+		return err
+	}
+
+	for _, texture := range wl.Textures {
+		palette := texture.Palette
+		if palette == nil {
+			if !wl.HasPalette {
+				return errors.New("[Error] ExportAllTextures: No palette available for " + texture.Name + ", import one first")
+			}
+			palette = &wl.Palette
+		}
+
+		mipCount := 1
+		if allMipLevels {
+			mipCount = 4
+		}
+
+		for level := 0; level < mipCount; level++ {
+			exportErr := ExportMipTexture(texture, level, *palette, outputFolder)
+			if exportErr != nil {
+				return errors.New("[Error] ExportAllTextures: Cannot export texture - " + texture.Name + " - " + exportErr.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+// '{' textures use the last palette index as transparency, '*' (liquids) is written as '#'
+func ExportMipTexture(texture MipTexture, mipLevel int, palette Palette, outputFolder string) error {
+	width := int(texture.Width >> mipLevel)
+	height := int(texture.Height >> mipLevel)
+	pixels := texture.MipLevels[mipLevel]
+	isMasked := strings.HasPrefix(texture.Name, "{")
+
+	textureImg := image.NewRGBA(image.Rect(0, 0, width, height))
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			colorIdx := pixels[x+y*width]
+			if isMasked && colorIdx == 255 {
+				continue
+			}
+
+			pixelColor := palette[colorIdx]
+			textureImg.Set(x, y, color.RGBA{pixelColor.Red, pixelColor.Green, pixelColor.Blue, 255})
+		}
+	}
+
+	// the name comes from the archive, it can't leave the output folder
+	filename := sanitizeFSName(strings.ReplaceAll(texture.Name, "*", "#"))
+	if mipLevel > 0 {
+		filename += "_mip" + strconv.Itoa(mipLevel)
+	}
+
+	textureFile, err := os.Create(outputFolder + "/" + filename + ".png")
+	if err != nil {
+		return errors.New("[Error] ExportMipTexture: Cannot create the target file for the texture - " + texture.Name + " - " + err.Error())
+	}
+
+	defer textureFile.Close()
+	png.Encode(textureFile, textureImg)
+
+	return nil
+}
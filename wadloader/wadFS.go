@@ -0,0 +1,423 @@
+package wadloader
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// namespace markers (X_START/X_END) become directories, unknown prefixes use their lowercase name
+var namespaceDirNames = map[string]string{
+	"S":  "sprites",
+	"SS": "sprites",
+	"F":  "flats",
+	"FF": "flats",
+	"P":  "patches",
+	"PP": "patches",
+	"TX": "textures",
+	"C":  "colormaps",
+	"A":  "acs",
+	"V":  "voices",
+	"HI": "hires",
+	"VX": "voxels",
+	"MS": "music",
+}
+
+// lumps that can follow a map header besides MapLumpsNames
+var extraMapLumpsNames = []string{"BEHAVIOR", "SCRIPTS", "LEAFS", "LIGHTS", "MACROS", "ZNODES", "DIALOGUE"}
+
+type WADFS struct {
+	wl   *WADLoader
+	root *wadFSNode
+}
+
+type wadFSNode struct {
+	name      string
+	isDir     bool
+	namespace string
+	lump      Lump
+	children  []*wadFSNode
+	byName    map[string]*wadFSNode
+}
+
+func (wl *WADLoader) FS() (*WADFS, error) {
+	if wl.WADReader == nil || len(wl.WADLumps) < 1 {
+		return nil, errors.New("[Error] FS: No WAD data or lumps loaded")
+	}
+
+	wfs := &WADFS{wl: wl, root: newWADFSDir("")}
+	wfs.buildTree()
+
+	return wfs, nil
+}
+
+func newWADFSDir(name string) *wadFSNode {
+	return &wadFSNode{name: name, isDir: true, byName: make(map[string]*wadFSNode)}
+}
+
+func (wfs *WADFS) buildTree() {
+	lumps := wfs.wl.WADLumps
+	dirStack := []*wadFSNode{wfs.root}
+
+	for idx := 0; idx < len(lumps); idx++ {
+		lump := lumps[idx]
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+		currentDir := dirStack[len(dirStack)-1]
+
+		if prefix, ok := namespaceMarkerPrefix(lumpName, "_START"); ok && lump.LumpSize == 0 {
+			dirName := namespaceDirName(prefix)
+
+			nsDir, exists := currentDir.byName[dirName]
+			if !exists || !nsDir.isDir {
+				nsDir = newWADFSDir(dirName)
+				nsDir.namespace = dirName
+				currentDir.addChild(nsDir)
+			}
+
+			dirStack = append(dirStack, nsDir)
+			continue
+		}
+
+		if prefix, ok := namespaceMarkerPrefix(lumpName, "_END"); ok && lump.LumpSize == 0 {
+			dirName := namespaceDirName(prefix)
+
+			for i := len(dirStack) - 1; i > 0; i-- {
+				if dirStack[i].namespace == dirName {
+					dirStack = dirStack[:i]
+					break
+				}
+			}
+			continue
+		}
+
+		if isMapHeader(lumps, idx) {
+			idx = wfs.addMap(lumps, idx)
+			continue
+		}
+
+		if !wfs.wl.IsLumpInBounds(lump) {
+			continue
+		}
+
+		currentDir.addChild(&wadFSNode{name: lumpName, lump: lump})
+	}
+}
+
+// adds the map lumps under maps/<NAME>/, returns the index of the last map lump
+func (wfs *WADFS) addMap(lumps []Lump, headerIdx int) int {
+	headerName := string(bytes.Trim(lumps[headerIdx].LumpName[:], "\x00"))
+
+	mapsDir, exists := wfs.root.byName["maps"]
+	if !exists || !mapsDir.isDir {
+		mapsDir = newWADFSDir("maps")
+		wfs.root.addChild(mapsDir)
+	}
+
+	mapDir := newWADFSDir(headerName)
+	mapsDir.addChild(mapDir)
+
+	if lumps[headerIdx].LumpSize > 0 && wfs.wl.IsLumpInBounds(lumps[headerIdx]) {
+		mapDir.addChild(&wadFSNode{name: headerName, lump: lumps[headerIdx]})
+	}
+
+	isUDMF := false
+	lastIdx := headerIdx
+
+	for idx := headerIdx + 1; idx < len(lumps); idx++ {
+		lumpName := string(bytes.Trim(lumps[idx].LumpName[:], "\x00"))
+
+		if idx == headerIdx+1 && lumpName == "TEXTMAP" {
+			isUDMF = true
+		}
+
+		if !isUDMF && !isMapDataLumpName(lumpName) {
+			break
+		}
+
+		lastIdx = idx
+
+		if wfs.wl.IsLumpInBounds(lumps[idx]) {
+			mapDir.addChild(&wadFSNode{name: lumpName, lump: lumps[idx]})
+		}
+
+		if isUDMF && lumpName == "ENDMAP" {
+			break
+		}
+	}
+
+	return lastIdx
+}
+
+func (dir *wadFSNode) addChild(child *wadFSNode) {
+	child.name = sanitizeFSName(child.name)
+
+	// duplicated lump names keep directory order: NAME, NAME~1, NAME~2...
+	baseName := child.name
+	for dup := 1; ; dup++ {
+		if _, exists := dir.byName[child.name]; !exists {
+			break
+		}
+		child.name = baseName + "~" + strconv.Itoa(dup)
+	}
+
+	dir.byName[child.name] = child
+	dir.children = append(dir.children, child)
+}
+
+// '\' is written as '^' like PK3s do, as it isn't portable in file names
+func sanitizeFSName(name string) string {
+	name = strings.ReplaceAll(name, "\\", "^")
+	name = strings.ReplaceAll(name, "/", "_")
+
+	if name == "" || name == "." || name == ".." {
+		return "_" + name
+	}
+
+	return name
+}
+
+func namespaceMarkerPrefix(lumpName string, suffix string) (string, bool) {
+	if !strings.HasSuffix(lumpName, suffix) || len(lumpName) == len(suffix) {
+		return "", false
+	}
+
+	return strings.TrimSuffix(lumpName, suffix), true
+}
+
+func namespaceDirName(prefix string) string {
+	if dirName, ok := namespaceDirNames[prefix]; ok {
+		return dirName
+	}
+
+	return strings.ToLower(prefix)
+}
+
+func isMapHeader(lumps []Lump, idx int) bool {
+	if idx+1 >= len(lumps) {
+		return false
+	}
+
+	nextName := string(bytes.Trim(lumps[idx+1].LumpName[:], "\x00"))
+	return nextName == "THINGS" || nextName == "TEXTMAP"
+}
+
+func isMapDataLumpName(lumpName string) bool {
+	for _, name := range MapLumpsNames {
+		if name == lumpName {
+			return true
+		}
+	}
+
+	for _, name := range extraMapLumpsNames {
+		if name == lumpName {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (wfs *WADFS) lookup(name string) (*wadFSNode, error) {
+	if !fs.ValidPath(name) {
+		return nil, fs.ErrInvalid
+	}
+
+	node := wfs.root
+	if name == "." {
+		return node, nil
+	}
+
+	for _, elem := range strings.Split(name, "/") {
+		if !node.isDir {
+			return nil, fs.ErrNotExist
+		}
+
+		child, ok := node.byName[elem]
+		if !ok {
+			return nil, fs.ErrNotExist
+		}
+
+		node = child
+	}
+
+	return node, nil
+}
+
+func (wfs *WADFS) Open(name string) (fs.File, error) {
+	node, err := wfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	if node.isDir {
+		return &wadFSDir{node: node}, nil
+	}
+
+	lumpReader, err := wfs.wl.LumpReader(node.lump)
+	if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+
+	return &wadFSFile{node: node, reader: lumpReader}, nil
+}
+
+func (wfs *WADFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	node, err := wfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: err}
+	}
+
+	if !node.isDir {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: errors.New("not a directory")}
+	}
+
+	return node.sortedEntries(), nil
+}
+
+func (wfs *WADFS) Stat(name string) (fs.FileInfo, error) {
+	node, err := wfs.lookup(name)
+	if err != nil {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+	}
+
+	return wadFSInfo{node: node}, nil
+}
+
+func (node *wadFSNode) sortedEntries() []fs.DirEntry {
+	entries := make([]fs.DirEntry, 0, len(node.children))
+	for _, child := range node.children {
+		entries = append(entries, fs.FileInfoToDirEntry(wadFSInfo{node: child}))
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Name() < entries[j].Name()
+	})
+
+	return entries
+}
+
+type wadFSInfo struct {
+	node *wadFSNode
+}
+
+func (fi wadFSInfo) Name() string {
+	if fi.node.name == "" {
+		return "."
+	}
+	return fi.node.name
+}
+
+func (fi wadFSInfo) Size() int64 {
+	if fi.node.isDir {
+		return 0
+	}
+	return int64(fi.node.lump.LumpSize)
+}
+
+func (fi wadFSInfo) Mode() fs.FileMode {
+	if fi.node.isDir {
+		return fs.ModeDir | 0555
+	}
+	return 0444
+}
+
+func (fi wadFSInfo) ModTime() time.Time { return time.Time{} }
+func (fi wadFSInfo) IsDir() bool        { return fi.node.isDir }
+func (fi wadFSInfo) Sys() any           { return nil }
+
+type wadFSFile struct {
+	node   *wadFSNode
+	reader *io.SectionReader
+}
+
+func (f *wadFSFile) Stat() (fs.FileInfo, error) { return wadFSInfo{node: f.node}, nil }
+func (f *wadFSFile) Read(p []byte) (int, error) { return f.reader.Read(p) }
+func (f *wadFSFile) Close() error               { return nil }
+
+func (f *wadFSFile) Seek(offset int64, whence int) (int64, error) {
+	return f.reader.Seek(offset, whence)
+}
+
+func (f *wadFSFile) ReadAt(p []byte, off int64) (int, error) {
+	return f.reader.ReadAt(p, off)
+}
+
+type wadFSDir struct {
+	node    *wadFSNode
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *wadFSDir) Stat() (fs.FileInfo, error) { return wadFSInfo{node: d.node}, nil }
+func (d *wadFSDir) Close() error               { return nil }
+
+func (d *wadFSDir) Read(p []byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.node.name, Err: errors.New("is a directory")}
+}
+
+func (d *wadFSDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	if d.entries == nil {
+		d.entries = d.node.sortedEntries()
+	}
+
+	remaining := d.entries[d.offset:]
+
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+
+	d.offset += count
+
+	return remaining[:count], nil
+}
+
+// writes every file of fsys into outputFolder keeping the directory tree
+func ExtractFS(fsys fs.FS, outputFolder string) error {
+	if outputFolder == "" {
+		return errors.New("[Error] ExtractFS: No folder name specified")
+	}
+
+	return fs.WalkDir(fsys, ".", func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		targetPath := filepath.Join(outputFolder, filepath.FromSlash(filePath))
+
+		if entry.IsDir() {
+			err = os.MkdirAll(targetPath, 0755)
+			if err != nil {
+				return errors.New("[Error] ExtractFS: Cannot create the folder " + targetPath + " - " + err.Error())
+			}
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, filePath)
+		if err != nil {
+			return errors.New("[Error] ExtractFS: Cannot read " + filePath + " - " + err.Error())
+		}
+
+		err = os.WriteFile(targetPath, data, 0644)
+		if err != nil {
+			return errors.New("[Error] ExtractFS: Cannot write " + targetPath + " - " + err.Error())
+		}
+
+		return nil
+	})
+}
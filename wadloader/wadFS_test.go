@@ -0,0 +1,106 @@
+package wadloader
+
+import (
+	"bytes"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+)
+
+func buildFSTestWAD() []byte {
+	var ww WADWriter
+
+	ww.AddLump("PLAYPAL", make([]byte, 768))
+	ww.AddLump("DEMO1", []byte("first"))
+	ww.AddLump("DEMO1", []byte("second"))
+	ww.AddLump("DEMO1", []byte("third"))
+
+	ww.AddMarker("MAP01")
+	for _, lumpName := range MapLumpsNames {
+		ww.AddLump(lumpName, []byte(lumpName))
+	}
+
+	ww.AddMarker("S_START")
+	ww.AddLump("TROOA1", testPatch(4, 4, 1))
+	ww.AddLump("TROOA1", testPatch(4, 4, 2))
+	ww.AddMarker("S_END")
+
+	ww.AddMarker("F_START")
+	ww.AddMarker("F1_START")
+	ww.AddLump("FLOOR0_1", make([]byte, 4096))
+	ww.AddMarker("F1_END")
+	ww.AddMarker("F_END")
+
+	// an unknown namespace and a second block of the sprites one
+	ww.AddMarker("XX_START")
+	ww.AddLump("THING", []byte("xx"))
+	ww.AddMarker("XX_END")
+
+	ww.AddMarker("SS_START")
+	ww.AddLump("BOSSA1", testPatch(4, 4, 3))
+	ww.AddMarker("SS_END")
+
+	return ww.Bytes()
+}
+
+func TestWADFS(t *testing.T) {
+	data := buildFSTestWAD()
+
+	wad := WADLoader{}
+	err := wad.LoadFromReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wad.ReadWADLumps()
+
+	wadFS, err := wad.FS()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{
+		"PLAYPAL", "DEMO1", "DEMO1~1", "DEMO1~2",
+		"maps/MAP01/THINGS", "maps/MAP01/BLOCKMAP",
+		"sprites/TROOA1", "sprites/TROOA1~1", "sprites/BOSSA1",
+		"flats/f1/FLOOR0_1",
+		"xx/THING",
+	}
+
+	err = fstest.TestFS(wadFS, expected...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// duplicates keep the directory order
+	for name, want := range map[string]string{"DEMO1": "first", "DEMO1~1": "second", "DEMO1~2": "third"} {
+		got, err := fs.ReadFile(wadFS, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != want {
+			t.Errorf("%s holds %q, want %q", name, got, want)
+		}
+	}
+
+	mapEntries, err := fs.ReadDir(wadFS, "maps/MAP01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(mapEntries) != len(MapLumpsNames) {
+		t.Fatalf("maps/MAP01 has %d entries, want %d", len(mapEntries), len(MapLumpsNames))
+	}
+	for _, lumpName := range MapLumpsNames {
+		got, err := fs.ReadFile(wadFS, "maps/MAP01/"+lumpName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != lumpName {
+			t.Errorf("maps/MAP01/%s holds %q", lumpName, got)
+		}
+	}
+
+	// map lumps don't leak into the root
+	if _, err := fs.Stat(wadFS, "THINGS"); err == nil {
+		t.Error("THINGS is listed at the root")
+	}
+}
@@ -2,24 +2,44 @@ package wadloader
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"os"
-	"strings"
 )
 
 type WADLoader struct {
 	WADBuffer   []byte
+	WADReader   io.ReaderAt
+	WADSize     int64
 	WADParser   WADParser
 	WADFilename string
 	WADHeader   WADHeader
 	WADLumps    WADLumps
 
-	Palettes []Palette
-	Maps     []Map
-	Music    []MusicLump
-	Sprites  []Patch
-	Patches  []Patch
-	Flats    []Flat
+	Palettes  []Palette
+	Colormaps []Colormap
+	Maps      []Map
+	Music     []MusicLump
+	PCSounds  []PCSpeakerSound
+	Sprites   []Patch
+	Patches   []Patch
+	Flats     []Flat
+	Textures  []Texture
+	// patches outside of the namespaces and raw fullscreen images
+	Graphics []Patch
+	Screens  []RawScreen
+
+	// max goroutines used when decoding and exporting, < 1 uses every CPU
+	Workers int
+
+	// exported graphics are seen through this colormap when set (light level or invulnerability)
+	RenderColormap *Colormap
+
+	// game specific rules, the Doom ones are used when nil
+	Profile *GameProfile
+
+	wadCloser io.Closer
 }
 
 func (wl *WADLoader) OpenAndLoad(wadFilename string) {
@@ -33,14 +53,109 @@ func (wl *WADLoader) OpenAndLoad(wadFilename string) {
 
 	wl.WADBuffer = wadBuffer
 
-	wl.WADParser.setupByteReader(wl.WADBuffer)
-	wl.WADHeader = wl.WADParser.readHeaderData()
+	err := wl.LoadFromReaderAt(bytes.NewReader(wl.WADBuffer), int64(len(wl.WADBuffer)))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+}
+
+// streams the WAD from disk instead of buffering it, Close() releases the file
+func (wl *WADLoader) OpenAndStream(wadFilename string) error {
+	wl.WADFilename = wadFilename
+
+	wadFile, err := os.Open(wl.WADFilename)
+	if err != nil {
+		return errors.New("[Error] OpenAndStream: Couldn't open the WAD file - " + err.Error())
+	}
+
+	fileInfo, err := wadFile.Stat()
+	if err != nil {
+		wadFile.Close()
+		return errors.New("[Error] OpenAndStream: Couldn't get the WAD file size - " + err.Error())
+	}
+
+	err = wl.LoadFromReaderAt(wadFile, fileInfo.Size())
+	if err != nil {
+		wadFile.Close()
+		return err
+	}
+
+	wl.wadCloser = wadFile
+
+	return nil
+}
+
+func (wl *WADLoader) LoadFromReaderAt(r io.ReaderAt, size int64) error {
+	if r == nil {
+		return errors.New("[Error] LoadFromReaderAt: No reader provided")
+	}
+
+	if size < 12 {
+		return errors.New("[Error] LoadFromReaderAt: Not enough data to read the WAD header")
+	}
+
+	wl.WADReader = r
+	wl.WADSize = size
+
+	wl.WADParser.setupReaderAt(r, size)
+
+	header, err := wl.WADParser.readHeaderData()
+	if err != nil {
+		return err
+	}
+
+	wl.WADHeader = header
+
+	return nil
+}
+
+func (wl *WADLoader) Close() error {
+	if wl.wadCloser == nil {
+		return nil
+	}
+
+	err := wl.wadCloser.Close()
+	wl.wadCloser = nil
+
+	return err
+}
+
+// lumps are read lazily, the returned reader only covers the lump data
+func (wl *WADLoader) LumpReader(lump Lump) (*io.SectionReader, error) {
+	if wl.WADReader == nil {
+		return nil, errors.New("[Error] LumpReader: No WAD data loaded")
+	}
+
+	if !wl.IsLumpInBounds(lump) {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+		return nil, errors.New("[Error] LumpReader: Lump " + lumpName + " points outside of the WAD file")
+	}
+
+	return io.NewSectionReader(wl.WADReader, int64(lump.LumpOffset), int64(lump.LumpSize)), nil
+}
+
+func (wl *WADLoader) ReadLumpData(lump Lump) ([]byte, error) {
+	lumpReader, err := wl.LumpReader(lump)
+	if err != nil {
+		return nil, err
+	}
+
+	lumpData := make([]byte, lump.LumpSize)
+
+	_, err = io.ReadFull(lumpReader, lumpData)
+	if err != nil {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+		return nil, errors.New("[Error] ReadLumpData: Cannot read lump data of " + lumpName + " - " + err.Error())
+	}
+
+	return lumpData, nil
 }
 
 type WADLumps []Lump
 
 func (wl *WADLoader) ReadWADLumps() {
-	if wl.WADBuffer == nil || wl.WADHeader.LumpEntries < 1 {
+	if wl.WADReader == nil || wl.WADHeader.LumpEntries < 1 {
 		fmt.Println("[Error] readWADLumps: Insufficient data to read WAD Directories")
 		os.Exit(0)
 	}
@@ -49,6 +164,12 @@ func (wl *WADLoader) ReadWADLumps() {
 
 	for i := 0; i < int(wl.WADHeader.LumpEntries); i++ {
 		dirData := wl.WADParser.readLumpInfo(dirOffset + int64(i*16))
+
+		if !wl.IsLumpInBounds(dirData) {
+			lumpName := string(bytes.Trim(dirData.LumpName[:], "\x00"))
+			fmt.Println("[Warn] readWADLumps: Lump", lumpName, "points outside of the WAD file, its data will be ignored")
+		}
+
 		wl.WADLumps = append(wl.WADLumps, dirData)
 	}
 }
@@ -74,7 +195,8 @@ func (wl *WADLoader) DetectMaps() []MapRawLumps {
 
 	// map lumps use a 0 byte marker and complies with the minimum types of lumps
 	for idx, lump := range wl.WADLumps {
-		if lump.LumpSize != 0 || !strings.HasPrefix(string(lump.LumpName[:]), "E") {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+		if lump.LumpSize != 0 || !(doomMapNameRegex.MatchString(lumpName) || mapxxNameRegex.MatchString(lumpName)) {
 			continue
 		}
 
@@ -98,7 +220,10 @@ func (wl *WADLoader) DetectMaps() []MapRawLumps {
 			}
 
 			if len(neededMapLumps) < 1 {
-				currentMapLumps.MapName = string(bytes.Trim(lump.LumpName[:], "\x00"))
+				currentMapLumps.MapName = lumpName
+				if behaviorLump, found := wl.findMapBehavior(idx); found {
+					currentMapLumps.Lumps = append(currentMapLumps.Lumps, behaviorLump)
+				}
 				rawMaps = append(rawMaps, currentMapLumps)
 				break
 			}
@@ -108,44 +233,87 @@ func (wl *WADLoader) DetectMaps() []MapRawLumps {
 	return rawMaps
 }
 
+// Hexen format maps carry a BEHAVIOR lump after the Doom ones
+func (wl *WADLoader) findMapBehavior(mapIdx int) (Lump, bool) {
+	for _, nextLump := range wl.WADLumps[mapIdx+1:] {
+		nextLumpName := string(bytes.Trim(nextLump.LumpName[:], "\x00"))
+
+		if nextLumpName == "BEHAVIOR" {
+			return nextLump, true
+		}
+
+		if !isMapDataLumpName(nextLumpName) {
+			break
+		}
+	}
+
+	return Lump{}, false
+}
+
 func (wl *WADLoader) LoadMapLumps(allMapsRaw []MapRawLumps) {
 	if len(allMapsRaw) < 1 {
 		fmt.Println("[Warn] LoadMapLumps: No maps inside the provided slice")
 		return
 	}
 
-	for _, currMap := range allMapsRaw {
-		var newMap Map
-		newMap.Name = currMap.MapName
+	maps := make([]Map, len(allMapsRaw))
+
+	runWorkers(len(allMapsRaw), wl.workerCount(), func(idx int) error {
+		maps[idx] = wl.parseMapRawLumps(allMapsRaw[idx])
+		return nil
+	})
+
+	wl.Maps = append(wl.Maps, maps...)
+}
+
+func (wl *WADLoader) parseMapRawLumps(currMap MapRawLumps) Map {
+	var newMap Map
+	newMap.Name = currMap.MapName
+	newMap.IsHexenFormat = wl.profile().HexenMapFormat
+
+	for _, currLump := range currMap.Lumps {
+		if string(bytes.Trim(currLump.LumpName[:], "\x00")) == "BEHAVIOR" {
+			newMap.IsHexenFormat = true
+		}
+	}
 
-		for _, currLump := range currMap.Lumps {
-			lumpNameStr := string(bytes.Trim(currLump.LumpName[:], "\x00"))
+	for _, currLump := range currMap.Lumps {
+		lumpNameStr := string(bytes.Trim(currLump.LumpName[:], "\x00"))
 
-			switch lumpNameStr {
-			case "THINGS":
+		switch lumpNameStr {
+		case "THINGS":
+			if newMap.IsHexenFormat {
+				newMap.HexenThings = wl.WADParser.parseMapHexenThings(currLump)
+				newMap.Things = hexenThingsToThings(newMap.HexenThings)
+			} else {
 				newMap.Things = wl.WADParser.parseMapThings(currLump)
-			case "LINEDEFS":
+			}
+		case "LINEDEFS":
+			if newMap.IsHexenFormat {
+				newMap.HexenLinedefs = wl.WADParser.parseMapHexenLinedefs(currLump)
+				newMap.Linedefs = hexenLinedefsToLinedefs(newMap.HexenLinedefs)
+			} else {
 				newMap.Linedefs = wl.WADParser.parseMapLinedefs(currLump)
-			case "SIDEDEFS":
-				newMap.Sidedefs = wl.WADParser.parseMapSidedefs(currLump)
-			case "VERTEXES":
-				newMap.Vertexes = wl.WADParser.parseMapVertexes(currLump)
-			case "SEGS":
-				newMap.Segs = wl.WADParser.parseMapSegs(currLump)
-			case "SSECTORS":
-				newMap.SSectors = wl.WADParser.parseMapSSectors(currLump)
-			case "NODES":
-				newMap.Nodes = wl.WADParser.parseMapNodes(currLump)
-			case "SECTORS":
-				newMap.Sectors = wl.WADParser.parseMapSectors(currLump)
-			case "REJECT":
-			case "BLOCKMAP":
-				// TODO: Missing rest of implementations
 			}
+		case "SIDEDEFS":
+			newMap.Sidedefs = wl.WADParser.parseMapSidedefs(currLump)
+		case "VERTEXES":
+			newMap.Vertexes = wl.WADParser.parseMapVertexes(currLump)
+		case "SEGS":
+			newMap.Segs = wl.WADParser.parseMapSegs(currLump)
+		case "SSECTORS":
+			newMap.SSectors = wl.WADParser.parseMapSSectors(currLump)
+		case "NODES":
+			newMap.Nodes = wl.WADParser.parseMapNodes(currLump)
+		case "SECTORS":
+			newMap.Sectors = wl.WADParser.parseMapSectors(currLump)
+		case "REJECT":
+		case "BLOCKMAP":
+			// TODO: Missing rest of implementations
 		}
-
-		wl.Maps = append(wl.Maps, newMap)
 	}
+
+	return newMap
 }
 
 type MusicLump struct {
@@ -161,16 +329,34 @@ func (wl *WADLoader) GetMusicLumps() ([]MusicLump, bool) {
 	}
 
 	var musicLumps []MusicLump
+	inMusicNamespace := false
+
+	profile := wl.profile()
+
+	var mapInfoMusic map[string]bool
+	if profile.MusicFromMapInfo {
+		mapInfoMusic = wl.musicLumpNamesFromMapInfo()
+	}
 
 	for _, lump := range wl.WADLumps {
+		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+
+		// PK3 music folders are loaded between MS_START and MS_END markers
 		if lump.LumpSize == 0 {
+			if lumpName == "MS_START" {
+				inMusicNamespace = true
+			} else if lumpName == "MS_END" {
+				inMusicNamespace = false
+			}
 			continue
 		}
 
-		// music lumps names start with "D_"
-		lumpName := string(bytes.Trim(lump.LumpName[:], "\x00"))
+		if !wl.IsLumpInBounds(lump) {
+			continue
+		}
 
-		if !(strings.HasPrefix(lumpName, "D_")) {
+		// music lumps names depend on the game, "D_" for Doom
+		if !profile.isMusicLumpName(lumpName, mapInfoMusic) && !inMusicNamespace {
 			continue
 		}
 
@@ -213,3 +399,25 @@ func (wl *WADLoader) LoadGraphics() {
 	wl.Patches = patches
 	wl.Flats = flats
 }
+
+// creates the export folder (and its parents) if needed, returns it without the trailing slash
+func createFolder(folderName string) (string, error) {
+	if folderName == "" {
+		return folderName, errors.New("[Error] createFolder: No folder name specified")
+	}
+
+	lastChar := folderName[len(folderName)-1:]
+	if lastChar == "/" && len(folderName) > 1 {
+		folderName = folderName[:len(folderName)-1]
+	}
+
+	_, err := os.Stat(folderName)
+	if err != nil {
+		err = os.MkdirAll(folderName, 0755)
+		if err != nil {
+			return folderName, errors.New("[Error] createFolder: Cannot create the target folder")
+		}
+	}
+
+	return folderName, nil
+}
@@ -1,57 +1,55 @@
 package wadloader
 
 import (
-	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 )
 
-
+// all reads are position independent so a parser can be shared between goroutines
 type WADParser struct {
-	byteReader *bytes.Reader
+	reader io.ReaderAt
+	size   int64
 }
 
-func (wp *WADParser) setupByteReader(b []byte) {
-	wp.byteReader = bytes.NewReader(b)
+func (wp *WADParser) setupReaderAt(r io.ReaderAt, size int64) {
+	wp.reader = r
+	wp.size = size
 }
 
-
 type WADHeader struct {
-	WadType [4]byte
-	LumpEntries uint32
+	WadType             [4]byte
+	LumpEntries         uint32
 	LumpDirectoryOffset uint32
 }
 
-func (wp *WADParser) readHeaderData() WADHeader {
-	wp.checkValidByteReader()
-	
+func (wp *WADParser) readHeaderData() (WADHeader, error) {
+	wp.checkValidReader()
+
 	var wadHeader WADHeader
-	err := binary.Read(wp.byteReader, binary.LittleEndian, &wadHeader)
+	err := binary.Read(io.NewSectionReader(wp.reader, 0, 12), binary.LittleEndian, &wadHeader)
 
 	if err != nil {
-		fmt.Println("[Error] readHeaderData: Invalid data when reading the WAD Header:", err)
-		os.Exit(1)
+		return wadHeader, errors.New("[Error] readHeaderData: Invalid data when reading the WAD Header: " + err.Error())
 	}
 
-	return wadHeader
+	return wadHeader, nil
 }
 
-
 type Lump struct {
 	LumpOffset uint32
-	LumpSize uint32
-	LumpName [8]byte
+	LumpSize   uint32
+	LumpName   [8]byte
 }
 
 func (wp *WADParser) readLumpInfo(seekAt int64) Lump {
-	wp.checkValidByteReader()
+	wp.checkValidReader()
 
-	wp.byteReader.Seek(seekAt, io.SeekStart)
-	
 	var lumpData Lump
-	err := binary.Read(wp.byteReader, binary.LittleEndian, &lumpData)
+	err := binary.Read(io.NewSectionReader(wp.reader, seekAt, 16), binary.LittleEndian, &lumpData)
 
 	if err != nil {
 		fmt.Println("[Error] readLumpInfo: Invalid data when reading WADs lump data:", err)
@@ -61,9 +59,31 @@ func (wp *WADParser) readLumpInfo(seekAt int64) Lump {
 	return lumpData
 }
 
-func (wp *WADParser) checkValidByteReader() {
-	if (wp.byteReader == nil) {
-		fmt.Println("[Error] No available byte reader, invoke it using setupByteReader(). Aborting execution")
+func (wp *WADParser) readAt(offset int64, size int) ([]byte, error) {
+	wp.checkValidReader()
+
+	if offset < 0 || offset+int64(size) > wp.size {
+		return nil, errors.New("[Error] readAt: Cannot read " + strconv.Itoa(size) + " bytes at offset " + strconv.FormatInt(offset, 10) + ", out of bounds")
+	}
+
+	data := make([]byte, size)
+
+	// ReadAt may report io.EOF along with a complete read at the end of the data
+	n, err := wp.reader.ReadAt(data, offset)
+	if n < size {
+		return nil, errors.New("[Error] readAt: Cannot read data - " + err.Error())
+	}
+
+	return data, nil
+}
+
+func (wp *WADParser) readLump(lump Lump) ([]byte, error) {
+	return wp.readAt(int64(lump.LumpOffset), int(lump.LumpSize))
+}
+
+func (wp *WADParser) checkValidReader() {
+	if wp.reader == nil {
+		fmt.Println("[Error] No available reader, invoke it using setupReaderAt(). Aborting execution")
 		os.Exit(1)
 	}
 }
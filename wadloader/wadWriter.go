@@ -0,0 +1,90 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"os"
+	"strings"
+)
+
+type WADWriter struct {
+	WadType string
+	lumps   []writerLump
+}
+
+type writerLump struct {
+	name [8]byte
+	data []byte
+}
+
+func (ww *WADWriter) AddLump(name string, data []byte) {
+	ww.lumps = append(ww.lumps, writerLump{name: LumpNameFromString(name), data: data})
+}
+
+func (ww *WADWriter) AddMarker(name string) {
+	ww.AddLump(name, nil)
+}
+
+func (ww *WADWriter) LumpCount() int {
+	return len(ww.lumps)
+}
+
+func (ww *WADWriter) Bytes() []byte {
+	wadType := ww.WadType
+	if wadType == "" {
+		wadType = "PWAD"
+	}
+
+	var header WADHeader
+	copy(header.WadType[:], wadType)
+	header.LumpEntries = uint32(len(ww.lumps))
+
+	// lump data goes right after the header and the directory at the end
+	var lumpData bytes.Buffer
+	directory := make([]Lump, 0, len(ww.lumps))
+
+	for _, lump := range ww.lumps {
+		var offset uint32
+		if len(lump.data) > 0 {
+			offset = uint32(12 + lumpData.Len())
+		}
+
+		directory = append(directory, Lump{
+			LumpOffset: offset,
+			LumpSize:   uint32(len(lump.data)),
+			LumpName:   lump.name,
+		})
+
+		lumpData.Write(lump.data)
+	}
+
+	header.LumpDirectoryOffset = uint32(12 + lumpData.Len())
+
+	var wadData bytes.Buffer
+	binary.Write(&wadData, binary.LittleEndian, header)
+	wadData.Write(lumpData.Bytes())
+	binary.Write(&wadData, binary.LittleEndian, directory)
+
+	return wadData.Bytes()
+}
+
+func (ww *WADWriter) WriteFile(filename string) error {
+	if filename == "" {
+		return errors.New("[Error] WriteFile: No filename specified")
+	}
+
+	err := os.WriteFile(filename, ww.Bytes(), 0644)
+	if err != nil {
+		return errors.New("[Error] WriteFile: Cannot write the WAD file - " + err.Error())
+	}
+
+	return nil
+}
+
+// lump names are upper case and at most 8 characters long
+func LumpNameFromString(name string) [8]byte {
+	var lumpName [8]byte
+	copy(lumpName[:], strings.ToUpper(name))
+	return lumpName
+}
@@ -0,0 +1,224 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+)
+
+// 16 bits PCM, channels interleaved
+type WAVAudio struct {
+	SampleRate int
+	Channels   int
+	Samples    []int16
+}
+
+type wavHeader struct {
+	RiffID        [4]byte
+	RiffSize      uint32
+	WaveID        [4]byte
+	FmtID         [4]byte
+	FmtSize       uint32
+	AudioFormat   uint16
+	Channels      uint16
+	SampleRate    uint32
+	ByteRate      uint32
+	BlockAlign    uint16
+	BitsPerSample uint16
+	DataID        [4]byte
+	DataSize      uint32
+}
+
+func (wa *WAVAudio) Encode() []byte {
+	dataSize := uint32(len(wa.Samples) * 2)
+
+	header := wavHeader{
+		RiffID:        [4]byte{'R', 'I', 'F', 'F'},
+		RiffSize:      36 + dataSize,
+		WaveID:        [4]byte{'W', 'A', 'V', 'E'},
+		FmtID:         [4]byte{'f', 'm', 't', ' '},
+		FmtSize:       16,
+		AudioFormat:   1,
+		Channels:      uint16(wa.Channels),
+		SampleRate:    uint32(wa.SampleRate),
+		ByteRate:      uint32(wa.SampleRate * wa.Channels * 2),
+		BlockAlign:    uint16(wa.Channels * 2),
+		BitsPerSample: 16,
+		DataID:        [4]byte{'d', 'a', 't', 'a'},
+		DataSize:      dataSize,
+	}
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, header)
+	binary.Write(&buf, binary.LittleEndian, wa.Samples)
+
+	return buf.Bytes()
+}
+
+func (wa *WAVAudio) WriteFile(filename string) error {
+	err := os.WriteFile(filename, wa.Encode(), 0644)
+	if err != nil {
+		return errors.New("[Error] WAVAudio.WriteFile: Cannot write " + filename + " - " + err.Error())
+	}
+
+	return nil
+}
+
+const (
+	wavFormatPCM        = 1
+	wavFormatFloat      = 3
+	wavFormatExtensible = 0xFFFE
+)
+
+// reads PCM (8 to 32 bits) and 32 bits float WAV files into 16 bits samples
+func DecodeWAV(data []byte) (WAVAudio, error) {
+	var audio WAVAudio
+
+	if len(data) < 12 || string(data[0:4]) != "RIFF" || string(data[8:12]) != "WAVE" {
+		return audio, errors.New("[Error] DecodeWAV: Missing the RIFF WAVE header")
+	}
+
+	var format, bitsPerSample int
+	var samples []byte
+	foundFormat := false
+
+	pos := 12
+	for pos+8 <= len(data) {
+		chunkID := string(data[pos : pos+4])
+		chunkLength := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		chunkStart := pos + 8
+		chunkEnd := chunkStart + chunkLength
+		if chunkEnd > len(data) || chunkEnd < chunkStart {
+			chunkEnd = len(data)
+		}
+		chunk := data[chunkStart:chunkEnd]
+
+		switch chunkID {
+		case "fmt ":
+			if len(chunk) < 16 {
+				return audio, errors.New("[Error] DecodeWAV: The fmt chunk is too short")
+			}
+			format = int(binary.LittleEndian.Uint16(chunk[0:]))
+			audio.Channels = int(binary.LittleEndian.Uint16(chunk[2:]))
+			audio.SampleRate = int(binary.LittleEndian.Uint32(chunk[4:]))
+			bitsPerSample = int(binary.LittleEndian.Uint16(chunk[14:]))
+
+			// the extensible format keeps the real one in the first bytes of the sub format GUID
+			if format == wavFormatExtensible && len(chunk) >= 26 {
+				format = int(binary.LittleEndian.Uint16(chunk[24:]))
+			}
+			foundFormat = true
+		case "data":
+			samples = chunk
+		}
+
+		// chunks are padded to an even length
+		pos = chunkStart + chunkLength + chunkLength&1
+	}
+
+	if !foundFormat || samples == nil {
+		return audio, errors.New("[Error] DecodeWAV: Missing the fmt or data chunk")
+	}
+
+	if audio.Channels < 1 || audio.SampleRate < 1 {
+		return audio, errors.New("[Error] DecodeWAV: Invalid channel count or sample rate")
+	}
+
+	validFormat := (format == wavFormatPCM && bitsPerSample >= 8 && bitsPerSample <= 32 && bitsPerSample%8 == 0) ||
+		(format == wavFormatFloat && bitsPerSample == 32)
+	if !validFormat {
+		return audio, fmt.Errorf("[Error] DecodeWAV: Unsupported format %d with %d bits per sample, only PCM and 32 bits float are read", format, bitsPerSample)
+	}
+
+	sampleSize := bitsPerSample / 8
+	audio.Samples = make([]int16, len(samples)/sampleSize)
+
+	for idx := range audio.Samples {
+		sample := samples[idx*sampleSize : (idx+1)*sampleSize]
+
+		switch {
+		case format == wavFormatFloat:
+			value := math.Float32frombits(binary.LittleEndian.Uint32(sample)) * 32767
+			audio.Samples[idx] = clampSample(float64(value))
+		case sampleSize == 1:
+			// 8 bits samples are unsigned
+			audio.Samples[idx] = int16(int(sample[0])-128) << 8
+		default:
+			// the top 2 bytes of a little endian signed sample
+			audio.Samples[idx] = int16(binary.LittleEndian.Uint16(sample[sampleSize-2:]))
+		}
+	}
+
+	// a trailing partial frame is dropped
+	audio.Samples = audio.Samples[:len(audio.Samples)/audio.Channels*audio.Channels]
+
+	return audio, nil
+}
+
+// averages the channels into one
+func (wa *WAVAudio) Mono() WAVAudio {
+	mono := WAVAudio{SampleRate: wa.SampleRate, Channels: 1}
+	if wa.Channels < 2 {
+		mono.Samples = append(mono.Samples, wa.Samples...)
+		return mono
+	}
+
+	mono.Samples = make([]int16, len(wa.Samples)/wa.Channels)
+	for idx := range mono.Samples {
+		sum := 0
+		for channel := 0; channel < wa.Channels; channel++ {
+			sum += int(wa.Samples[idx*wa.Channels+channel])
+		}
+		mono.Samples[idx] = int16(sum / wa.Channels)
+	}
+
+	return mono
+}
+
+// mono resampling: lowering the rate averages the samples each output sample covers,
+// raising it interpolates between them
+func (wa *WAVAudio) Resample(sampleRate int) WAVAudio {
+	resampled := WAVAudio{SampleRate: sampleRate, Channels: 1}
+	if wa.SampleRate == sampleRate || len(wa.Samples) == 0 {
+		resampled.Samples = append(resampled.Samples, wa.Samples...)
+		return resampled
+	}
+
+	ratio := float64(wa.SampleRate) / float64(sampleRate)
+	count := int(float64(len(wa.Samples)) / ratio)
+	resampled.Samples = make([]int16, count)
+
+	for idx := range resampled.Samples {
+		position := float64(idx) * ratio
+
+		if ratio > 1 {
+			start, end := int(position), int(position+ratio)
+			if end > len(wa.Samples) {
+				end = len(wa.Samples)
+			}
+			if end <= start {
+				end = start + 1
+			}
+
+			sum := 0
+			for _, sample := range wa.Samples[start:end] {
+				sum += int(sample)
+			}
+			resampled.Samples[idx] = int16(sum / (end - start))
+			continue
+		}
+
+		first := int(position)
+		second := first + 1
+		if second >= len(wa.Samples) {
+			second = first
+		}
+		fraction := position - float64(first)
+		resampled.Samples[idx] = int16(float64(wa.Samples[first])*(1-fraction) + float64(wa.Samples[second])*fraction)
+	}
+
+	return resampled
+}
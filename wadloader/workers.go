@@ -0,0 +1,60 @@
+package wadloader
+
+import (
+	"runtime"
+	"sync"
+)
+
+func (wl *WADLoader) workerCount() int {
+	if wl.Workers < 1 {
+		return runtime.NumCPU()
+	}
+
+	return wl.Workers
+}
+
+// runs job for every index in [0, count) using up to workers goroutines.
+// the error of the lowest failing index is returned so results are deterministic
+func runWorkers(count int, workers int, job func(idx int) error) error {
+	if count < 1 {
+		return nil
+	}
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	if workers > count {
+		workers = count
+	}
+
+	errs := make([]error, count)
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+
+	for w := 0; w < workers; w++ {
+		go func() {
+			defer wg.Done()
+			for idx := range jobs {
+				errs[idx] = job(idx)
+			}
+		}()
+	}
+
+	for idx := 0; idx < count; idx++ {
+		jobs <- idx
+	}
+
+	close(jobs)
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,252 @@
+package wadloader
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image/png"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+const (
+	workersTestSprites = 24
+	workersTestMaps    = 8
+)
+
+// a width x height patch with a single post per column
+func testPatch(width int, height int, color byte) []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, [4]int16{int16(width), int16(height), int16(width / 2), int16(height)})
+
+	columnStart := 8 + 4*width
+	columnSize := height + 5
+	for column := 0; column < width; column++ {
+		binary.Write(&buf, binary.LittleEndian, uint32(columnStart+column*columnSize))
+	}
+
+	for column := 0; column < width; column++ {
+		buf.Write([]byte{0, byte(height), 0})
+		buf.Write(bytes.Repeat([]byte{color + byte(column)}, height))
+		buf.Write([]byte{0, 0xFF})
+	}
+
+	return buf.Bytes()
+}
+
+func testMUS() []byte {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, musHeader{
+		ID:              [4]byte{'M', 'U', 'S', 0x1A},
+		ScoreLength:     7,
+		ScoreStart:      18,
+		PrimaryChannels: 1,
+		InstrumentCount: 1,
+	})
+	binary.Write(&buf, binary.LittleEndian, uint16(0))
+	// note on with a 70 ticks delay, note off, end of score
+	buf.Write([]byte{0x90, 0x80 | 60, 100, 70, 0x00, 60, 0x60})
+	return buf.Bytes()
+}
+
+func testMIDI() []byte {
+	track := []byte{0x00, 0x90, 60, 100, 0x60, 0x80, 60, 0, 0x00, 0xFF, 0x2F, 0x00}
+
+	var buf bytes.Buffer
+	buf.WriteString("MThd")
+	binary.Write(&buf, binary.BigEndian, uint32(6))
+	binary.Write(&buf, binary.BigEndian, [3]uint16{0, 1, 96})
+	buf.WriteString("MTrk")
+	binary.Write(&buf, binary.BigEndian, uint32(len(track)))
+	buf.Write(track)
+	return buf.Bytes()
+}
+
+// PLAYPAL, sprites, flats, maps and a few songs, enough lumps to keep several workers busy
+func buildWorkersTestWAD() []byte {
+	var ww WADWriter
+
+	playpal := make([]byte, 768*14)
+	for idx := range playpal {
+		playpal[idx] = byte(idx)
+	}
+	ww.AddLump("PLAYPAL", playpal)
+
+	for idx := 1; idx <= workersTestMaps; idx++ {
+		ww.AddMarker(fmt.Sprintf("MAP%02d", idx))
+		ww.AddLump("THINGS", bytes.Repeat([]byte{1, 0, 2, 0, 0, 0, 1, 0, 7, 0}, 4))
+		ww.AddLump("LINEDEFS", make([]byte, 14*3))
+		ww.AddLump("SIDEDEFS", make([]byte, 30*3))
+		ww.AddLump("VERTEXES", make([]byte, 4*3))
+		ww.AddLump("SEGS", make([]byte, 12*3))
+		ww.AddLump("SSECTORS", make([]byte, 4))
+		ww.AddLump("NODES", make([]byte, 28))
+		ww.AddLump("SECTORS", make([]byte, 26))
+		ww.AddLump("REJECT", make([]byte, 1))
+		ww.AddLump("BLOCKMAP", make([]byte, 10))
+	}
+
+	ww.AddMarker("S_START")
+	for idx := 0; idx < workersTestSprites; idx++ {
+		ww.AddLump(fmt.Sprintf("TST%cA0", 'A'+idx), testPatch(8+idx, 16, byte(idx)))
+	}
+	ww.AddMarker("S_END")
+
+	ww.AddMarker("F_START")
+	for idx := 0; idx < 4; idx++ {
+		ww.AddLump(fmt.Sprintf("FLAT%d", idx), bytes.Repeat([]byte{byte(idx * 16)}, 64*64))
+	}
+	ww.AddMarker("F_END")
+
+	for idx := 0; idx < 6; idx++ {
+		if idx%2 == 0 {
+			ww.AddLump(fmt.Sprintf("D_SONG%d", idx), testMUS())
+		} else {
+			ww.AddLump(fmt.Sprintf("D_SONG%d", idx), testMIDI())
+		}
+	}
+
+	return ww.Bytes()
+}
+
+func loadWorkersTestWAD(t *testing.T, workers int) *WADLoader {
+	t.Helper()
+
+	data := buildWorkersTestWAD()
+
+	wad := &WADLoader{Workers: workers}
+	err := wad.LoadFromReaderAt(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	wad.ReadWADLumps()
+
+	return wad
+}
+
+func readFolder(t *testing.T, folder string) map[string][]byte {
+	t.Helper()
+
+	entries, err := os.ReadDir(folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := make(map[string][]byte)
+	for _, entry := range entries {
+		data, err := os.ReadFile(filepath.Join(folder, entry.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		files[entry.Name()] = data
+	}
+	return files
+}
+
+func sortedFileNames(files map[string][]byte) []string {
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// the same WAD goes through one and several workers, the results must match. Run with -race
+func TestWorkersMatchSerialResults(t *testing.T) {
+	serial := loadWorkersTestWAD(t, 1)
+	parallel := loadWorkersTestWAD(t, 8)
+
+	for _, wad := range []*WADLoader{serial, parallel} {
+		wad.LoadPalettes()
+		wad.LoadGraphics()
+		wad.LoadMapLumps(wad.DetectMaps())
+
+		musicLumps, _ := wad.GetMusicLumps()
+		wad.Music = append(wad.Music, musicLumps...)
+	}
+
+	if len(parallel.Sprites) != workersTestSprites || len(parallel.Flats) != 4 {
+		t.Fatalf("got %d sprites and %d flats, want %d and 4", len(parallel.Sprites), len(parallel.Flats), workersTestSprites)
+	}
+
+	for idx, sprite := range parallel.Sprites {
+		want := serial.Sprites[idx]
+		if !reflect.DeepEqual(sprite, want) {
+			t.Errorf("sprite %d is %s with one worker and %s with several", idx, want.Name, sprite.Name)
+		}
+	}
+
+	if len(parallel.Maps) != workersTestMaps || len(serial.Maps) != workersTestMaps {
+		t.Fatalf("got %d maps with several workers and %d with one, want %d", len(parallel.Maps), len(serial.Maps), workersTestMaps)
+	}
+	for idx, loadedMap := range parallel.Maps {
+		if loadedMap.Name != serial.Maps[idx].Name || len(loadedMap.Things) != 4 || len(loadedMap.Linedefs) != 3 {
+			t.Errorf("map %d is %s with %d things and %d linedefs, want %s with 4 and 3",
+				idx, loadedMap.Name, len(loadedMap.Things), len(loadedMap.Linedefs), serial.Maps[idx].Name)
+		}
+	}
+
+	exports := map[string]func(wad *WADLoader, folder string) error{
+		"sprites": (*WADLoader).ExportAllSprites,
+		"songs":   (*WADLoader).ExportAllSongs,
+	}
+
+	for kind, export := range exports {
+		serialFolder := filepath.Join(t.TempDir(), kind)
+		parallelFolder := filepath.Join(t.TempDir(), kind)
+
+		if err := export(serial, serialFolder); err != nil {
+			t.Fatal(err)
+		}
+		if err := export(parallel, parallelFolder); err != nil {
+			t.Fatal(err)
+		}
+
+		serialFiles := readFolder(t, serialFolder)
+		parallelFiles := readFolder(t, parallelFolder)
+
+		if len(parallelFiles) == 0 || len(parallelFiles) != len(serialFiles) {
+			t.Fatalf("%s: %d files exported with several workers, %d with one", kind, len(parallelFiles), len(serialFiles))
+		}
+		for _, name := range sortedFileNames(parallelFiles) {
+			if !bytes.Equal(parallelFiles[name], serialFiles[name]) {
+				t.Errorf("%s: %s differs between one and several workers", kind, name)
+			}
+		}
+	}
+}
+
+// a PWAD can carry several lumps with the same name, only the last one must reach the disk
+func TestExportAllSpritesKeepsLastDuplicate(t *testing.T) {
+	wad := loadWorkersTestWAD(t, 8)
+	wad.LoadPalettes()
+
+	wad.Sprites = nil
+	for idx := 0; idx < 16; idx++ {
+		wad.Sprites = append(wad.Sprites, Patch{Name: "DUPEA0", Width: uint16(idx + 1), Height: 1})
+	}
+	wad.Sprites = append(wad.Sprites, Patch{Name: "OTHRA0", Width: 2, Height: 1})
+	wad.Patches, wad.Graphics, wad.Screens, wad.Flats = nil, nil, nil, nil
+
+	folder := filepath.Join(t.TempDir(), "sprites")
+	if err := wad.ExportAllSprites(folder); err != nil {
+		t.Fatal(err)
+	}
+
+	files := readFolder(t, folder)
+	if len(files) != 2 {
+		t.Fatalf("exported %v, want DUPEA0.png and OTHRA0.png", sortedFileNames(files))
+	}
+
+	config, err := png.DecodeConfig(bytes.NewReader(files["DUPEA0.png"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Width != 16 {
+		t.Errorf("DUPEA0.png is %d pixels wide, want 16 from the last lump", config.Width)
+	}
+}